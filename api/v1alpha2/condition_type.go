@@ -7,12 +7,68 @@ import (
 )
 
 const (
-	HMetaReady   string = "HMetaReady"
-	HStoreReady  string = "HStoreReady"
-	HServerReady string = "HServerReady"
-	GatewayReady string = "GatewayReady"
-	ConsoleReady string = "ConsoleReady"
-	Ready        string = "Ready"
+	HMetaReady       string = "HMetaReady"
+	HStoreReady      string = "HStoreReady"
+	HServerReady     string = "HServerReady"
+	AdminServerReady string = "AdminServerReady"
+	GatewayReady     string = "GatewayReady"
+	ConsoleReady     string = "ConsoleReady"
+	Ready            string = "Ready"
+	// Paused is true while the reconciliation is suspended by spec.pause.
+	Paused string = "Paused"
+	// ReconcileError is true while the last reconciliation failed, its reason
+	// and message describe the failure.
+	ReconcileError string = "ReconcileError"
+
+	// HStoreBootstrapped and HServerBootstrapped record that the one-off
+	// bootstrap command has been executed. Unlike the readiness conditions
+	// above, they never go back to False.
+	HStoreBootstrapped  string = "HStoreBootstrapped"
+	HServerBootstrapped string = "HServerBootstrapped"
+)
+
+// Reasons used by the conditions above.
+const (
+	ReasonBootstrapped       string = "Bootstrapped"
+	ReasonNotBootstrapped    string = "NotBootstrapped"
+	ReasonWorkloadNotFound   string = "WorkloadNotFound"
+	ReasonReplicasNotReady   string = "ReplicasNotReady"
+	ReasonAllReplicasReady   string = "AllReplicasReady"
+	ReasonNodesUnreachable   string = "NodesUnreachable"
+	ReasonAllNodesReachable  string = "AllNodesReachable"
+	ReasonNodesNotRunning    string = "NodesNotRunning"
+	ReasonComponentsNotReady string = "ComponentsNotReady"
+	ReasonAllComponentsReady string = "AllComponentsReady"
+	ReasonPaused             string = "ReconciliationPaused"
+	ReasonResumed            string = "ReconciliationResumed"
+	ReasonReconcileSucceeded string = "ReconcileSucceeded"
+
+	// Reasons of the Ready condition of the resources in HStreamDB, e.g. Stream.
+	ReasonHStreamDBNotFound    string = "HStreamDBNotFound"
+	ReasonHStreamDBNotReady    string = "HStreamDBNotReady"
+	ReasonAdminCommandFailed   string = "AdminCommandFailed"
+	ReasonStreamCreated        string = "StreamCreated"
+	ReasonStreamSettingsDiffer string = "StreamSettingsDiffer"
+	ReasonStreamNotFound       string = "StreamNotFound"
+
+	ReasonSubscriptionCreated        string = "SubscriptionCreated"
+	ReasonSubscriptionSettingsDiffer string = "SubscriptionSettingsDiffer"
+	ReasonActiveConsumers            string = "ActiveConsumers"
+
+	ReasonQueryCreated    string = "QueryCreated"
+	ReasonQueryRecreated  string = "QueryRecreated"
+	ReasonQueryRestarted  string = "QueryRestarted"
+	ReasonQueryRunning    string = "QueryRunning"
+	ReasonQueryNotRunning string = "QueryNotRunning"
+	ReasonViewCreated     string = "ViewCreated"
+	ReasonViewRecreated   string = "ViewRecreated"
+
+	// Reasons of the events recorded once the resources are deleted from
+	// HStreamDB.
+	ReasonStreamDeleted       string = "StreamDeleted"
+	ReasonSubscriptionDeleted string = "SubscriptionDeleted"
+	ReasonQueryDeleted        string = "QueryDeleted"
+	ReasonViewDeleted         string = "ViewDeleted"
 )
 
 func (hdb *HStreamDB) IsConditionTrue(conditionType string) bool {
@@ -33,6 +89,15 @@ func (hdb *HStreamDB) GetCondition(conditionType string) (int, *metav1.Condition
 	return -1, nil
 }
 
+// RemoveCondition removes the condition with the given type, if present.
+func (hdb *HStreamDB) RemoveCondition(conditionType string) {
+	index, _ := hdb.GetCondition(conditionType)
+	if index == -1 {
+		return
+	}
+	hdb.Status.Conditions = append(hdb.Status.Conditions[:index], hdb.Status.Conditions[index+1:]...)
+}
+
 func (hdb *HStreamDB) SetCondition(condition metav1.Condition) {
 	now := metav1.Now()
 	condition.LastTransitionTime = now
@@ -56,6 +56,12 @@ type HStreamDBSpec struct {
 
 	Config Config `json:"config,omitempty"`
 
+	// Pause suspends the reconciliation of the cluster, so that its workloads
+	// can be operated manually without being reverted by the operator.
+	// The status is still updated while the reconciliation is paused.
+	// +optional
+	Pause *Pause `json:"pause,omitempty"`
+
 	Gateway     *Gateway   `json:"gateway,omitempty"`
 	Console     *Component `json:"console,omitempty"`
 	AdminServer Component  `json:"adminServer,omitempty"`
@@ -76,6 +82,29 @@ type ExternalHMeta struct {
 	Namespace string `json:"namespace"`
 }
 
+type Pause struct {
+	// Components lists the components whose reconciliation is suspended,
+	// e.g. hstore or hserver. All components are suspended if it is empty.
+	// +optional
+	Components []ComponentType `json:"components,omitempty"`
+}
+
+// IsPaused reports whether the reconciliation of the component is suspended.
+func (p *Pause) IsPaused(ct ComponentType) bool {
+	if p == nil {
+		return false
+	}
+	if len(p.Components) == 0 {
+		return true
+	}
+	for _, c := range p.Components {
+		if c == ct {
+			return true
+		}
+	}
+	return false
+}
+
 type Config struct {
 	// MetadataReplicateAcross metadata replication must less than or equal to HStore replicas.
 	// If this is not specified, it will be set to HStore replicas or 3 if HStore replica more than 3
@@ -107,6 +136,37 @@ type HStreamDBStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 	// HMeta store the status of HMeta cluster
 	HMeta HMetaStatus `json:"hmeta"`
+	// Reconciliation records the outcome of each step of the last reconciliation
+	// +optional
+	Reconciliation []ReconcileStep `json:"reconciliation,omitempty"`
+}
+
+type ReconcileStepOutcome string
+
+const (
+	// ReconcileStepSucceeded means the step has finished its work.
+	ReconcileStepSucceeded ReconcileStepOutcome = "Succeeded"
+	// ReconcileStepDelayed means the step has not finished its work, but the
+	// steps depending on it can proceed.
+	ReconcileStepDelayed ReconcileStepOutcome = "Delayed"
+	// ReconcileStepBlocked means the step is waiting for something, the steps
+	// depending on it are skipped.
+	ReconcileStepBlocked ReconcileStepOutcome = "Blocked"
+	// ReconcileStepFailed means the step encountered an error, the steps
+	// depending on it are skipped.
+	ReconcileStepFailed ReconcileStepOutcome = "Failed"
+	// ReconcileStepSkipped means the step did not run because one of its
+	// dependencies was blocked or failed.
+	ReconcileStepSkipped ReconcileStepOutcome = "Skipped"
+	// ReconcileStepPaused means the step did not run because the
+	// reconciliation of its component is paused.
+	ReconcileStepPaused ReconcileStepOutcome = "Paused"
+)
+
+type ReconcileStep struct {
+	Name    string               `json:"name"`
+	Outcome ReconcileStepOutcome `json:"outcome"`
+	Message string               `json:"message,omitempty"`
 }
 
 type HMetaStatus struct {
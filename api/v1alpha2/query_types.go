@@ -0,0 +1,120 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// QuerySpec defines the desired state of Query
+type QuerySpec struct {
+	// HStreamDBRef refers to the HStreamDB in which the query runs.
+	// +kubebuilder:validation:Required
+	HStreamDBRef HStreamDBReference `json:"hstreamDBRef"`
+
+	// SQL is the statement creating the continuous query, e.g.
+	// "CREATE STREAM large_orders AS SELECT * FROM orders WHERE amount > 100;".
+	// The query is recreated once the statement changes.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	SQL string `json:"sql"`
+
+	// DeletionPolicy decides whether the query is terminated and deleted from
+	// HStreamDB once the Query is deleted.
+	// +kubebuilder:default:=Retain
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// SQLState is the state of a query or view in HStreamDB.
+type SQLState string
+
+const (
+	// SQLStatePending means the query or view is waiting for the HStreamDB.
+	SQLStatePending SQLState = "Pending"
+	// SQLStateCreated means the query or view has been submitted to HStreamDB.
+	SQLStateCreated SQLState = "Created"
+	// SQLStateFailed means the query or view could not be submitted or observed.
+	SQLStateFailed SQLState = "Failed"
+)
+
+// SQLTaskStatus is the observed state of the task running a query or view.
+type SQLTaskStatus struct {
+	// QueryID is the ID of the task in HStreamDB.
+	// +optional
+	QueryID string `json:"queryID,omitempty"`
+
+	// NodeID is the ID of the HServer node running the task.
+	// +optional
+	NodeID int32 `json:"nodeID,omitempty"`
+
+	// TaskStatus is the status of the task reported by HStreamDB, e.g. RUNNING.
+	// +optional
+	TaskStatus string `json:"taskStatus,omitempty"`
+
+	// SQLHash is the hash of the submitted statement, a different hash of the
+	// spec means the task has to be recreated.
+	// +optional
+	SQLHash string `json:"sqlHash,omitempty"`
+}
+
+// QueryStatus defines the observed state of Query
+type QueryStatus struct {
+	// State is the state of the query in HStreamDB.
+	// +optional
+	State SQLState `json:"state,omitempty"`
+
+	// ObservedGeneration is the generation of the Query last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	SQLTaskStatus `json:",inline"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="HStreamDB",type="string",JSONPath=".spec.hstreamDBRef.name"
+//+kubebuilder:printcolumn:name="Query ID",type="string",JSONPath=".status.queryID"
+//+kubebuilder:printcolumn:name="Node",type="integer",JSONPath=".status.nodeID"
+//+kubebuilder:printcolumn:name="Task Status",type="string",JSONPath=".status.taskStatus"
+//+kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Query is the Schema for the queries API
+type Query struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   QuerySpec   `json:"spec,omitempty"`
+	Status QueryStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// QueryList contains a list of Query
+type QueryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Query `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Query{}, &QueryList{})
+}
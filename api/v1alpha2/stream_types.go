@@ -0,0 +1,133 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StreamSpec defines the desired state of Stream
+// +kubebuilder:validation:XValidation:rule="has(self.streamName) == has(oldSelf.streamName) && (!has(self.streamName) || self.streamName == oldSelf.streamName)",message="streamName is immutable"
+type StreamSpec struct {
+	// HStreamDBRef refers to the HStreamDB in which the stream is created.
+	// +kubebuilder:validation:Required
+	HStreamDBRef HStreamDBReference `json:"hstreamDBRef"`
+
+	// StreamName is the name of the stream in HStreamDB, defaults to the name
+	// of the Stream. It can not be changed once the Stream is created.
+	// +optional
+	StreamName string `json:"streamName,omitempty"`
+
+	// ReplicationFactor is the number of replicas of the records.
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	ReplicationFactor int32 `json:"replicationFactor,omitempty"`
+
+	// BacklogRetention is how long the records are kept, e.g. "24h". The
+	// default of HStreamDB is used if not set.
+	// +optional
+	BacklogRetention *metav1.Duration `json:"backlogRetention,omitempty"`
+
+	// ShardCount is the number of shards of the stream.
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	ShardCount int32 `json:"shardCount,omitempty"`
+
+	// DeletionPolicy decides whether the stream is deleted from HStreamDB
+	// once the Stream is deleted.
+	// +kubebuilder:default:=Retain
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// StreamState is the state of a stream in HStreamDB.
+type StreamState string
+
+const (
+	// StreamStatePending means the stream is waiting for the HStreamDB.
+	StreamStatePending StreamState = "Pending"
+	// StreamStateCreated means the stream exists in HStreamDB.
+	StreamStateCreated StreamState = "Created"
+	// StreamStateFailed means the stream could not be created or observed.
+	StreamStateFailed StreamState = "Failed"
+)
+
+// StreamStatus defines the observed state of Stream
+type StreamStatus struct {
+	// State is the state of the stream in HStreamDB.
+	// +optional
+	State StreamState `json:"state,omitempty"`
+
+	// ObservedGeneration is the generation of the Stream last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ReplicationFactor is the replication factor reported by HStreamDB.
+	// +optional
+	ReplicationFactor int32 `json:"replicationFactor,omitempty"`
+
+	// BacklogRetention is the backlog retention reported by HStreamDB.
+	// +optional
+	BacklogRetention *metav1.Duration `json:"backlogRetention,omitempty"`
+
+	// ShardCount is the number of shards reported by HStreamDB.
+	// +optional
+	ShardCount int32 `json:"shardCount,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="HStreamDB",type="string",JSONPath=".spec.hstreamDBRef.name"
+//+kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".status.replicationFactor"
+//+kubebuilder:printcolumn:name="Shards",type="integer",JSONPath=".status.shardCount"
+//+kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Stream is the Schema for the streams API
+type Stream struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   StreamSpec   `json:"spec,omitempty"`
+	Status StreamStatus `json:"status,omitempty"`
+}
+
+// GetStreamName returns the name of the stream in HStreamDB.
+func (s *Stream) GetStreamName() string {
+	if s.Spec.StreamName != "" {
+		return s.Spec.StreamName
+	}
+	return s.Name
+}
+
+//+kubebuilder:object:root=true
+
+// StreamList contains a list of Stream
+type StreamList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Stream `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Stream{}, &StreamList{})
+}
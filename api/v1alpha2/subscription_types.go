@@ -0,0 +1,164 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SubscriptionOffset is the position in the stream a subscription starts from.
+// +kubebuilder:validation:Enum=Earliest;Latest
+type SubscriptionOffset string
+
+const (
+	SubscriptionOffsetEarliest SubscriptionOffset = "Earliest"
+	SubscriptionOffsetLatest   SubscriptionOffset = "Latest"
+)
+
+// SubscriptionSpec defines the desired state of Subscription
+type SubscriptionSpec struct {
+	// HStreamDBRef refers to the HStreamDB in which the subscription is created.
+	// +kubebuilder:validation:Required
+	HStreamDBRef HStreamDBReference `json:"hstreamDBRef"`
+
+	// SubscriptionName is the ID of the subscription in HStreamDB, defaults
+	// to the name of the Subscription.
+	// +optional
+	SubscriptionName string `json:"subscriptionName,omitempty"`
+
+	// StreamName is the name of the stream in HStreamDB to subscribe to.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	StreamName string `json:"streamName"`
+
+	// AckTimeoutSeconds is how long a record may stay unacknowledged before
+	// it is delivered again.
+	// +kubebuilder:default:=60
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=36000
+	// +optional
+	AckTimeoutSeconds int32 `json:"ackTimeoutSeconds,omitempty"`
+
+	// MaxUnackedRecords is the number of unacknowledged records after which
+	// the delivery to the consumers is paused.
+	// +kubebuilder:default:=10000
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	MaxUnackedRecords int32 `json:"maxUnackedRecords,omitempty"`
+
+	// Offset is where the subscription starts in the stream.
+	// +kubebuilder:default:=Latest
+	// +optional
+	Offset SubscriptionOffset `json:"offset,omitempty"`
+
+	// DeletionPolicy decides whether the subscription is deleted from
+	// HStreamDB once the Subscription is deleted.
+	// +kubebuilder:default:=Retain
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// ForceDelete deletes the subscription even if it still has active
+	// consumers. Otherwise the deletion waits until all consumers are gone.
+	// +optional
+	ForceDelete bool `json:"forceDelete,omitempty"`
+}
+
+// SubscriptionState is the state of a subscription in HStreamDB.
+type SubscriptionState string
+
+const (
+	// SubscriptionStatePending means the subscription is waiting for the
+	// HStreamDB or the stream.
+	SubscriptionStatePending SubscriptionState = "Pending"
+	// SubscriptionStateCreated means the subscription exists in HStreamDB.
+	SubscriptionStateCreated SubscriptionState = "Created"
+	// SubscriptionStateFailed means the subscription could not be created or observed.
+	SubscriptionStateFailed SubscriptionState = "Failed"
+	// SubscriptionStateDeleting means the subscription waits for its consumers
+	// to be gone before being deleted.
+	SubscriptionStateDeleting SubscriptionState = "Deleting"
+)
+
+// SubscriptionStatus defines the observed state of Subscription
+type SubscriptionStatus struct {
+	// State is the state of the subscription in HStreamDB.
+	// +optional
+	State SubscriptionState `json:"state,omitempty"`
+
+	// ObservedGeneration is the generation of the Subscription last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// AckTimeoutSeconds is the ack timeout reported by HStreamDB.
+	// +optional
+	AckTimeoutSeconds int32 `json:"ackTimeoutSeconds,omitempty"`
+
+	// MaxUnackedRecords is the max unacked records reported by HStreamDB.
+	// +optional
+	MaxUnackedRecords int32 `json:"maxUnackedRecords,omitempty"`
+
+	// Consumers is the number of active consumers of the subscription.
+	// +optional
+	Consumers int32 `json:"consumers"`
+
+	// Backlog is the number of records not yet acknowledged by the consumers.
+	// +optional
+	Backlog int64 `json:"backlog"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=sub
+//+kubebuilder:printcolumn:name="HStreamDB",type="string",JSONPath=".spec.hstreamDBRef.name"
+//+kubebuilder:printcolumn:name="Stream",type="string",JSONPath=".spec.streamName"
+//+kubebuilder:printcolumn:name="Consumers",type="integer",JSONPath=".status.consumers"
+//+kubebuilder:printcolumn:name="Backlog",type="integer",JSONPath=".status.backlog"
+//+kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Subscription is the Schema for the subscriptions API
+type Subscription struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SubscriptionSpec   `json:"spec,omitempty"`
+	Status SubscriptionStatus `json:"status,omitempty"`
+}
+
+// GetSubscriptionName returns the ID of the subscription in HStreamDB.
+func (s *Subscription) GetSubscriptionName() string {
+	if s.Spec.SubscriptionName != "" {
+		return s.Spec.SubscriptionName
+	}
+	return s.Name
+}
+
+//+kubebuilder:object:root=true
+
+// SubscriptionList contains a list of Subscription
+type SubscriptionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Subscription `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Subscription{}, &SubscriptionList{})
+}
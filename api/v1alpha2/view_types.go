@@ -0,0 +1,101 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ViewSpec defines the desired state of View
+type ViewSpec struct {
+	// HStreamDBRef refers to the HStreamDB in which the view is created.
+	// +kubebuilder:validation:Required
+	HStreamDBRef HStreamDBReference `json:"hstreamDBRef"`
+
+	// ViewName is the name of the view in HStreamDB, defaults to the name of
+	// the View.
+	// +optional
+	ViewName string `json:"viewName,omitempty"`
+
+	// SQL is the SELECT statement materialized by the view, e.g.
+	// "SELECT user_id, COUNT(*) AS orders FROM orders GROUP BY user_id;".
+	// The view is recreated once the statement changes.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	SQL string `json:"sql"`
+
+	// DeletionPolicy decides whether the view is deleted from HStreamDB once
+	// the View is deleted.
+	// +kubebuilder:default:=Retain
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// ViewStatus defines the observed state of View
+type ViewStatus struct {
+	// State is the state of the view in HStreamDB.
+	// +optional
+	State SQLState `json:"state,omitempty"`
+
+	// ObservedGeneration is the generation of the View last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	SQLTaskStatus `json:",inline"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="HStreamDB",type="string",JSONPath=".spec.hstreamDBRef.name"
+//+kubebuilder:printcolumn:name="Query ID",type="string",JSONPath=".status.queryID"
+//+kubebuilder:printcolumn:name="Node",type="integer",JSONPath=".status.nodeID"
+//+kubebuilder:printcolumn:name="Task Status",type="string",JSONPath=".status.taskStatus"
+//+kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// View is the Schema for the views API
+type View struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ViewSpec   `json:"spec,omitempty"`
+	Status ViewStatus `json:"status,omitempty"`
+}
+
+// GetViewName returns the name of the view in HStreamDB.
+func (v *View) GetViewName() string {
+	if v.Spec.ViewName != "" {
+		return v.Spec.ViewName
+	}
+	return v.Name
+}
+
+//+kubebuilder:object:root=true
+
+// ViewList contains a list of View
+type ViewList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []View `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&View{}, &ViewList{})
+}
@@ -18,16 +18,117 @@ package v1beta1
 
 const ComponentTypeConnector = "connector"
 
+// Condition types of Connector, along with the Ready condition shared by all
+// resources.
+const (
+	// ConnectorProgressing means the connector pods of some streams are being
+	// created or rolled.
+	ConnectorProgressing = "Progressing"
+	// ConnectorDegraded means the connector of some streams failed to be
+	// reconciled.
+	ConnectorDegraded = "Degraded"
+	// ConnectorConfigValid means the config of the connector or template is
+	// valid against the schema of its type.
+	ConnectorConfigValid = "ConfigValid"
+	// ConnectorStorageSynced means the data PVCs of all streams match the
+	// storage of the connector. It is only set if the storage is.
+	ConnectorStorageSynced = "StorageSynced"
+)
+
+// Reasons of the Connector conditions.
+const (
+	ReasonAllStreamsReady  = "AllStreamsReady"
+	ReasonStreamsNotReady  = "StreamsNotReady"
+	ReasonRollingOut       = "RollingOut"
+	ReasonRolledOut        = "RolledOut"
+	ReasonStreamsFailed    = "StreamsFailed"
+	ReasonConfigInvalid    = "ConfigInvalid"
+	ReasonConfigValidated  = "ConfigValidated"
+	ReasonReconcileSucceed = "ReconcileSucceed"
+	ReasonStorageSynced    = "StorageSynced"
+	// ReasonStorageImmutable is also recorded as a Warning event once the
+	// changes of the immutable fields of a data PVC are ignored.
+	ReasonStorageImmutable = "StorageImmutable"
+)
+
+// Reasons of the Connector events.
+const (
+	// ReasonStreamRemoved is recorded once a resource of a stream removed from
+	// the Connector is deleted, or its data PVC is retained.
+	ReasonStreamRemoved = "StreamRemoved"
+)
+
+// ConnectorStreamKey is the label of the connector workloads recording the
+// stream they serve.
+const ConnectorStreamKey = "stream"
+
+// ConnectorConfigHashKey is the annotation of the connector pods recording the
+// hash of their config, so that the pods are rolled once the config changes.
+const ConnectorConfigHashKey = "hstream.io/connector-config-hash"
+
+// ConnectorType is the name of a ConnectorClass or a built-in connector type.
+// +kubebuilder:validation:MinLength=1
 type ConnectorType string
 
 const (
 	SinkElaticsearch ConnectorType = "sink-elasticsearch"
+	SinkMySQL        ConnectorType = "sink-mysql"
+	SinkPostgreSQL   ConnectorType = "sink-postgresql"
+	SinkMongoDB      ConnectorType = "sink-mongodb"
+	SinkKafka        ConnectorType = "sink-kafka"
+	SinkS3           ConnectorType = "sink-s3"
+	SourceMySQL      ConnectorType = "source-mysql"
+	SourcePostgreSQL ConnectorType = "source-postgresql"
+	SourceMongoDB    ConnectorType = "source-mongodb"
+)
+
+// ConnectorPatchType decides how the patches of a Connector are applied to the
+// config of its template.
+// +kubebuilder:validation:Enum=merge;json
+type ConnectorPatchType string
+
+const (
+	// ConnectorPatchMerge applies each patch as a JSON merge patch (RFC 7386),
+	// which merges nested objects and removes the fields set to null.
+	ConnectorPatchMerge ConnectorPatchType = "merge"
+	// ConnectorPatchJSON applies each patch as a JSON Patch (RFC 6902), which is
+	// a list of operations such as add, remove or replace.
+	ConnectorPatchJSON ConnectorPatchType = "json"
+)
+
+// ConnectorStorageRetentionPolicy decides what happens to the volume of a
+// stream once it is no longer used by the Connector.
+// +kubebuilder:validation:Enum=Retain;Delete
+type ConnectorStorageRetentionPolicy string
+
+const (
+	// ConnectorStorageRetain keeps the volume, which is released from the
+	// Connector and adopted again once the stream is added back.
+	ConnectorStorageRetain ConnectorStorageRetentionPolicy = "Retain"
+	// ConnectorStorageDelete deletes the volume.
+	ConnectorStorageDelete ConnectorStorageRetentionPolicy = "Delete"
 )
 
 var ConnectorImageMap = map[ConnectorType]string{
 	SinkElaticsearch: "hstreamdb/sink-elasticsearch:standalone",
+	SinkMySQL:        "hstreamdb/sink-mysql:standalone",
+	SinkPostgreSQL:   "hstreamdb/sink-postgresql:standalone",
+	SinkMongoDB:      "hstreamdb/sink-mongodb:standalone",
+	SinkKafka:        "hstreamdb/sink-kafka:standalone",
+	SinkS3:           "hstreamdb/sink-s3:standalone",
+	SourceMySQL:      "hstreamdb/source-mysql:standalone",
+	SourcePostgreSQL: "hstreamdb/source-postgresql:standalone",
+	SourceMongoDB:    "hstreamdb/source-mongodb:standalone",
 }
 
 var ConnectorContainerPortMap = map[ConnectorType]int32{
 	SinkElaticsearch: 9200,
+	SinkMySQL:        3306,
+	SinkPostgreSQL:   5432,
+	SinkMongoDB:      27017,
+	SinkKafka:        9092,
+	SinkS3:           9000,
+	SourceMySQL:      3306,
+	SourcePostgreSQL: 5432,
+	SourceMongoDB:    27017,
 }
@@ -19,7 +19,9 @@ package v1beta1
 import (
 	"encoding/json"
 
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -27,15 +29,15 @@ import (
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // ConnectorSpec defines the desired state of Connector
+// +kubebuilder:validation:XValidation:rule="has(self.hserverEndpoint) != has(self.hstreamDBRef)",message="exactly one of hserverEndpoint and hstreamDBRef must be set"
 type ConnectorSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
 	// Type is the type of the connector, typically used to verify that the type matches the configuration.
 	//
-	// Each connector type is associated with a connector image, which is used to create the connector container.
-	// View `ConnectorImageMap` for more details.
-	// +kubebuilder:validation:Enum=sink-elasticsearch
+	// Each connector type is resolved to the ConnectorClass of the same name, or to a built-in connector type,
+	// which defines the image of the connector container. View `ConnectorImageMap` for the built-in types.
 	// +kubebuilder:validation:Required
 	Type ConnectorType `json:"type"`
 
@@ -44,11 +46,26 @@ type ConnectorSpec struct {
 	TemplateName string `json:"templateName"`
 
 	// Streams is used to specify the streams that the connector will be applied to.
+	// A sink connector reads from each stream, while a source connector writes
+	// the captured changes into each stream. Each stream is served by a Deployment.
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinItems=1
 	Streams []string `json:"streams"`
 
+	// PatchType decides how GlobalPatch and Patches are applied to the configuration of the template,
+	// either as JSON merge patches (`merge`) or as JSON Patches (`json`).
+	// +kubebuilder:default:=merge
+	// +optional
+	PatchType ConnectorPatchType `json:"patchType,omitempty"`
+
+	// GlobalPatch is applied to the configuration of every stream, before the patch of the stream in Patches.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	GlobalPatch json.RawMessage `json:"globalPatch,omitempty"`
+
 	// Patches is used to specify the patches that will be applied to the connector configuration.
+	// It maps each stream to the patch of its configuration.
 	// +kubebuilder:validation:Schemaless
 	// +kubebuilder:validation:Type=object
 	// +kubebuilder:pruning:PreserveUnknownFields
@@ -57,8 +74,16 @@ type ConnectorSpec struct {
 
 	// HServerEndpoint is the endpoint of the HStreamDB server.
 	// For example: "hstreamdb-hserver:6570"
-	// +kube:validation:Required
-	HServerEndpoint string `json:"hserverEndpoint"`
+	// Exactly one of HServerEndpoint and HStreamDBRef must be set.
+	// +optional
+	HServerEndpoint string `json:"hserverEndpoint,omitempty"`
+
+	// HStreamDBRef refers to the HStreamDB that the connector connects to.
+	// The endpoint and TLS settings of HServer are resolved from it, and the
+	// connector pods are only created once the HStreamDB is ready.
+	// Exactly one of HServerEndpoint and HStreamDBRef must be set.
+	// +optional
+	HStreamDBRef *HStreamDBReference `json:"hstreamDBRef,omitempty"`
 
 	// ImageRegistry is used to specify the registry of the connector container image.
 	// +optional
@@ -77,16 +102,97 @@ type ConnectorSpec struct {
 	// Containers is used to add additional containers to the connector pod.
 	// +optional
 	Containers []corev1.Container `json:"containers,omitempty"`
+
+	// Storage provisions a PersistentVolumeClaim for each stream, which keeps the state of the connector,
+	// such as its offsets, across pod restarts. The state is kept in an emptyDir volume if unset.
+	// +optional
+	Storage *ConnectorStorage `json:"storage,omitempty"`
+}
+
+// ConnectorStorage declares the PersistentVolumeClaim of each stream of a Connector.
+type ConnectorStorage struct {
+	// Size is the requested size of the volume of each stream. It can only be increased,
+	// if the storage class allows volume expansion.
+	// +kubebuilder:validation:Required
+	Size resource.Quantity `json:"size"`
+
+	// StorageClassName is the name of the storage class of the volumes, defaults to the default storage class.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// AccessModes are the access modes of the volumes, defaults to ReadWriteOnce.
+	// +optional
+	AccessModes []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+
+	// RetentionPolicy decides whether the volume of a stream is deleted once the
+	// stream is removed. The volumes are always retained once the storage is unset.
+	// +kubebuilder:default:=Retain
+	// +optional
+	RetentionPolicy ConnectorStorageRetentionPolicy `json:"retentionPolicy,omitempty"`
+}
+
+// HStreamDBReference refers to an HStreamDB, which may be in another namespace
+// since the connectors only connect to HServer.
+type HStreamDBReference struct {
+	hapi.HStreamDBReference `json:",inline"`
+
+	// Namespace is the namespace of the HStreamDB, defaults to the namespace
+	// of the Connector.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ConnectorStreamStatus is the observed state of the connector serving a stream.
+type ConnectorStreamStatus struct {
+	// Stream is the name of the stream.
+	Stream string `json:"stream"`
+
+	// Deployment is the name of the Deployment running the connector for the stream.
+	Deployment string `json:"deployment"`
+
+	// ReadyReplicas is the number of ready connector pods.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// ConfigHash is the hash of the config last applied to the connector pods.
+	// +optional
+	ConfigHash string `json:"configHash,omitempty"`
+
+	// Error is the error that occurred when the stream was last reconciled.
+	// +optional
+	Error string `json:"error,omitempty"`
 }
 
 // ConnectorStatus defines the observed state of Connector
 type ConnectorStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// ObservedGeneration is the generation of the Connector last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Streams is the observed state of the connector serving each stream.
+	// +listType=map
+	// +listMapKey=stream
+	// +optional
+	Streams []ConnectorStreamStatus `json:"streams,omitempty"`
+
+	// ReadyStreams is the number of streams served by ready connector pods
+	// out of all streams, e.g. "1/2".
+	// +optional
+	ReadyStreams string `json:"readyStreams,omitempty"`
+
+	// Conditions are the Ready, Progressing, Degraded and ConfigValid conditions of the connector.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
+//+kubebuilder:printcolumn:name="Template",type="string",JSONPath=".spec.templateName"
+//+kubebuilder:printcolumn:name="Streams",type="string",JSONPath=".status.readyStreams"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+//+kubebuilder:printcolumn:name="Degraded",type="string",JSONPath=".status.conditions[?(@.type==\"Degraded\")].status",priority=1
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Connector is the Schema for the connectors API
 type Connector struct {
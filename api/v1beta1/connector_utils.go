@@ -37,3 +37,15 @@ func GenConnectorConfigMapNameForStream(connectorName, stream string) string {
 func GenConnectorDeploymentName(connectorName, stream string) string {
 	return connectorName + "-hc-" + strings.Replace(stream, "_", "-", -1)
 }
+
+// GenConnectorConfigSecretName returns the name of the Secret holding the
+// rendered config of the connector for a stream.
+func GenConnectorConfigSecretName(connectorName, stream string) string {
+	return GenConnectorDeploymentName(connectorName, stream) + "-config"
+}
+
+// GenConnectorDataPVCName returns the name of the PersistentVolumeClaim keeping
+// the state of the connector for a stream.
+func GenConnectorDataPVCName(connectorName, stream string) string {
+	return GenConnectorDeploymentName(connectorName, stream) + "-data"
+}
@@ -45,4 +45,9 @@ var _ = Describe("v1beta1/connector_utils", func() {
 		deploymentName := v1beta1.GenConnectorDeploymentName(connector, stream)
 		Expect(deploymentName).To(Equal("test-connector-hc-test-stream"))
 	})
+
+	It("should generate correct config secret name", func() {
+		secretName := v1beta1.GenConnectorConfigSecretName(connector, stream)
+		Expect(secretName).To(Equal("test-connector-hc-test-stream-config"))
+	})
 })
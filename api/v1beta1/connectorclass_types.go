@@ -0,0 +1,126 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultConnectorConfigMountPath is the directory where the config.json of
+	// a connector is mounted, unless its ConnectorClass specifies another one.
+	DefaultConnectorConfigMountPath = "/data/config"
+	// DefaultConnectorDataMountPath is the directory where a connector keeps its
+	// state, unless its ConnectorClass specifies another one.
+	DefaultConnectorDataMountPath = "/data"
+)
+
+// ConnectorClassSpec defines how the connectors of a type are run.
+type ConnectorClassSpec struct {
+	// Image is the image of the connector container. It is prefixed with
+	// `spec.imageRegistry` of the Connector if set.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+
+	// Ports are the default ports exposed by the connector container, the
+	// ports of `spec.container` of the Connector are appended to them.
+	// +optional
+	Ports []corev1.ContainerPort `json:"ports,omitempty"`
+
+	// Args are the arguments of the connector container, defaults to
+	// `run --config <configMountPath>/config.json`.
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// ConfigMountPath is the directory where the rendered config.json is
+	// mounted, defaults to /data/config.
+	// +optional
+	ConfigMountPath string `json:"configMountPath,omitempty"`
+
+	// DataMountPath is the directory where the connector keeps its state, such
+	// as its offsets, which is backed by the storage of the Connector. Defaults
+	// to /data.
+	// +optional
+	DataMountPath string `json:"dataMountPath,omitempty"`
+
+	// Schema is the JSON Schema of the connector config. The configs of the
+	// ConnectorTemplates and Connectors of the type are validated against it.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Schema json.RawMessage `json:"schema,omitempty"`
+}
+
+// GetConfigMountPath returns the directory where the config is mounted.
+func (s *ConnectorClassSpec) GetConfigMountPath() string {
+	if s.ConfigMountPath == "" {
+		return DefaultConnectorConfigMountPath
+	}
+	return s.ConfigMountPath
+}
+
+// GetDataMountPath returns the directory where the connector keeps its state.
+func (s *ConnectorClassSpec) GetDataMountPath() string {
+	if s.DataMountPath == "" {
+		return DefaultConnectorDataMountPath
+	}
+	return s.DataMountPath
+}
+
+// GetArgs returns the arguments of the connector container.
+func (s *ConnectorClassSpec) GetArgs() []string {
+	if len(s.Args) == 0 {
+		return []string{
+			"run",
+			"--config " + s.GetConfigMountPath() + "/config.json",
+		}
+	}
+	return s.Args
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Image",type="string",JSONPath=".spec.image"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ConnectorClass declares a connector type, whose name is referred to by
+// `spec.type` of the Connectors and ConnectorTemplates. It takes precedence
+// over the built-in connector type of the same name.
+type ConnectorClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ConnectorClassSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ConnectorClassList contains a list of ConnectorClass
+type ConnectorClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ConnectorClass `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ConnectorClass{}, &ConnectorClassList{})
+}
@@ -29,26 +29,41 @@ type ConnectorTemplateSpec struct {
 	// Important: Run "make" to regenerate code after modifying this file
 
 	// Type is the type of the connector template, typically used to verify that the type matches the configuration.
-	// +kubebuilder:validation:Enum=sink-elasticsearch
+	// It is either the name of a ConnectorClass or a built-in connector type.
 	// +kubebuilder:validation:Required
 	Type ConnectorType `json:"type"`
 
 	// Config is the configuration for the connector template.
 	// For details, see https://docs.hstream.io/ingest-and-distribute/connectors.html.
-	//
-	// Note: currently, only the `sink-elasticsearch` connector is supported.
 	// +kubebuilder:validation:Required
 	Config string `json:"config"`
 }
 
 // ConnectorTemplateStatus defines the observed state of ConnectorTemplate
 type ConnectorTemplateStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// ObservedGeneration is the generation of the ConnectorTemplate last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Connectors are the names of the Connectors using the template, whose
+	// configs are re-rendered once the template changes.
+	// +optional
+	Connectors []string `json:"connectors,omitempty"`
+
+	// Conditions report whether the config is valid against the schema of
+	// the connector type.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
+//+kubebuilder:printcolumn:name="Connectors",type="string",JSONPath=".status.connectors"
+//+kubebuilder:printcolumn:name="Valid",type="string",JSONPath=".status.conditions[?(@.type==\"ConfigValid\")].status"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // ConnectorTemplate is the Schema for the connectortemplates API
 type ConnectorTemplate struct {
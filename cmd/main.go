@@ -28,16 +28,22 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
 	"github.com/hstreamdb/hstream-operator/api/v1beta1"
 	"github.com/hstreamdb/hstream-operator/internal/controller"
+	webhookv1beta1 "github.com/hstreamdb/hstream-operator/internal/webhook/v1beta1"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -68,11 +74,19 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var adminClientMode string
+	var enableWebhooks bool
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&adminClientMode, "admin-client", string(admin.AdminClientModeExec),
+		"The way to send admin commands to HStreamDB clusters, exec or grpc. "+
+			"The grpc mode sends server commands to HServer directly and falls back to exec.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"Serve the validating webhooks of Connectors and ConnectorTemplates. "+
+			"The serving certificate is read from /tmp/k8s-webhook-server/serving-certs.")
 	opts := zap.Options{
 		TimeEncoder: zapcore.RFC3339TimeEncoder,
 	}
@@ -96,8 +110,30 @@ func main() {
 		zap.WriteTo(logWriter))
 	ctrl.SetLogger(logger)
 
+	adminMode, err := admin.ParseAdminClientMode(adminClientMode)
+	if err != nil {
+		setupLog.Error(err, "invalid admin client mode")
+		os.Exit(1)
+	}
+
+	// Only the pods of HStreamDB clusters are cached, they are listed to find
+	// the admin server pods.
+	hstreamPods, err := labels.NewRequirement(hapi.InstanceKey, selection.Exists, nil)
+	if err != nil {
+		setupLog.Error(err, "invalid pod selector")
+		os.Exit(1)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme:                 scheme,
+		Scheme: scheme,
+		NewCache: cache.BuilderWithOptions(cache.Options{
+			SelectorsByObject: cache.SelectorsByObject{
+				&corev1.Pod{}: {Label: labels.NewSelector().Add(*hstreamPods)},
+			},
+		}),
+		// Secrets are read from the API server, only their metadata is
+		// cached to watch them.
+		ClientDisableCacheFor:  []client.Object{&corev1.Secret{}},
 		MetricsBindAddress:     metricsAddr,
 		Port:                   9443,
 		HealthProbeBindAddress: probeAddr,
@@ -120,18 +156,29 @@ func main() {
 		os.Exit(1)
 	}
 
+	adminClientProvider, err := admin.NewAdminClientProvider(mgr.GetConfig(), logger,
+		admin.WithMode(adminMode),
+		// list the admin server pods from the informer cache of the manager
+		admin.WithPodReader(mgr.GetClient()),
+	)
+	if err != nil {
+		setupLog.Error(err, "unable to create admin client provider")
+		os.Exit(1)
+	}
+
 	if err = (&controller.HStreamDBReconciler{
 		Client:              mgr.GetClient(),
 		Scheme:              mgr.GetScheme(),
 		Recorder:            mgr.GetEventRecorderFor("hstreamdb-controller"),
-		AdminClientProvider: admin.NewAdminClientProvider(mgr.GetConfig(), logger),
+		AdminClientProvider: adminClientProvider,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "HStreamDB")
 		os.Exit(1)
 	}
 	if err = (&controller.ConnectorReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("connector-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Connector")
 		os.Exit(1)
@@ -143,6 +190,52 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "ConnectorTemplate")
 		os.Exit(1)
 	}
+	if err = (&controller.StreamReconciler{
+		Client:              mgr.GetClient(),
+		Scheme:              mgr.GetScheme(),
+		Recorder:            mgr.GetEventRecorderFor("stream-controller"),
+		AdminClientProvider: adminClientProvider,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Stream")
+		os.Exit(1)
+	}
+	if err = (&controller.SubscriptionReconciler{
+		Client:              mgr.GetClient(),
+		Scheme:              mgr.GetScheme(),
+		Recorder:            mgr.GetEventRecorderFor("subscription-controller"),
+		AdminClientProvider: adminClientProvider,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Subscription")
+		os.Exit(1)
+	}
+	if err = (&controller.QueryReconciler{
+		Client:              mgr.GetClient(),
+		Scheme:              mgr.GetScheme(),
+		Recorder:            mgr.GetEventRecorderFor("query-controller"),
+		AdminClientProvider: adminClientProvider,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Query")
+		os.Exit(1)
+	}
+	if err = (&controller.ViewReconciler{
+		Client:              mgr.GetClient(),
+		Scheme:              mgr.GetScheme(),
+		Recorder:            mgr.GetEventRecorderFor("view-controller"),
+		AdminClientProvider: adminClientProvider,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "View")
+		os.Exit(1)
+	}
+	if enableWebhooks {
+		if err = webhookv1beta1.SetupConnectorWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Connector")
+			os.Exit(1)
+		}
+		if err = webhookv1beta1.SetupConnectorTemplateWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ConnectorTemplate")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -17,8 +17,11 @@ limitations under the License.
 package admin
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strconv"
+	"sync/atomic"
 
 	"github.com/go-logr/logr"
 	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
@@ -27,65 +30,110 @@ import (
 	"github.com/hstreamdb/hstream-operator/pkg/executor"
 	"github.com/hstreamdb/hstream-operator/pkg/selector"
 	jsoniter "github.com/json-iterator/go"
-	"k8s.io/client-go/rest"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 )
 
 var json = jsoniter.ConfigCompatibleWithStandardLibrary
 
+// ErrNoReadyAdminPod is returned when no admin server pod is ready to run hadmin.
+var ErrNoReadyAdminPod = errors.New("no ready admin server pod")
+
+// IsCommandFailed reports whether the admin command ran but failed, rather
+// than it could not be run at all, e.g. when no admin server pod is ready.
+func IsCommandFailed(err error) bool {
+	var exitErr *executor.ExitError
+	var commandErr *CommandError
+	return errors.As(err, &exitErr) || errors.As(err, &commandErr)
+}
+
+// adminPodCursor rotates the admin server pods between calls.
+var adminPodCursor atomic.Uint32
+
 type AdminClient struct {
 	hdb      *hapi.HStreamDB
 	selector *selector.Selector
-	executor *executor.RemoteExecutor
+	executor executor.Executor
 	log      logr.Logger
 }
 
 // NewAdminClient generates an Admin client for a hStream
-func NewAdminClient(hdb *hapi.HStreamDB, restConfig *rest.Config, log logr.Logger) IAdminClient {
-	e, _ := executor.NewRemoteExecutor(restConfig)
-	selector := selector.NewSelector(e.Clientset)
-
+func NewAdminClient(hdb *hapi.HStreamDB, e executor.Executor, s *selector.Selector, log logr.Logger) *AdminClient {
 	return &AdminClient{
 		hdb:      hdb,
-		selector: selector,
+		selector: s,
 		executor: e,
 		log: log.WithValues("namespace", hdb.Namespace).
 			WithValues("instance", hdb.Name),
 	}
 }
 
-func (ac *AdminClient) call(args ...string) (string, error) {
+func (ac *AdminClient) call(ctx context.Context, args ...string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
 	command := executor.Command{
 		Command: "hadmin",
 		Args:    args,
 	}
 
-	pods, err := ac.selector.GetPods(
-		ac.hdb.Namespace,
-		&map[string]string{hapi.ComponentKey: string(hapi.ComponentTypeAdminServer)},
-		nil,
-	)
+	pods, err := ac.selector.GetReadyPods(ac.hdb.Namespace, &map[string]string{
+		hapi.InstanceKey:  ac.hdb.Name,
+		hapi.ComponentKey: string(hapi.ComponentTypeAdminServer),
+	})
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("failed to list admin server pods: %w", err)
+	}
+	if len(pods) == 0 {
+		return "", fmt.Errorf("%w in %s/%s", ErrNoReadyAdminPod, ac.hdb.Namespace, ac.hdb.Name)
 	}
 
-	return ac.executor.RunCommandInPod(pods[0].Name, ac.hdb.Namespace, command)
+	// Rotate the first pod between calls to spread the commands, and fail over
+	// to the next pod if the command could not be run.
+	start := int(adminPodCursor.Add(1) % uint32(len(pods)))
+	var errs []error
+	for i := range pods {
+		pod := pods[(start+i)%len(pods)]
+		result, err := ac.executor.RunCommandInPodWithContext(ctx, pod.Name, ac.hdb.Namespace, command)
+		if err == nil {
+			if result.Stderr != "" {
+				ac.log.Info("hadmin wrote to stderr", "pod", pod.Name, "command", command.ToString(), "stderr", result.Stderr)
+			}
+			return result.Stdout, nil
+		}
+
+		// The command ran but failed, running it in another pod would not help.
+		var exitErr *executor.ExitError
+		if errors.As(err, &exitErr) || ctx.Err() != nil {
+			return "", err
+		}
+
+		ac.log.Info("Failed to run hadmin in admin server pod, fail over to the next pod",
+			"pod", pod.Name, "error", err.Error())
+		errs = append(errs, fmt.Errorf("pod %s: %w", pod.Name, err))
+	}
+	return "", utilerrors.NewAggregate(errs)
 }
 
 // CallServer call hadmin server command with args.
-func (ac *AdminClient) CallServer(args ...string) (string, error) {
-	return ac.call(append([]string{"server"}, args...)...)
+func (ac *AdminClient) CallServer(ctx context.Context, args ...string) (string, error) {
+	return ac.call(ctx, append([]string{"server"}, args...)...)
 }
 
 // CallStore call hadmin store command with args.
-func (ac *AdminClient) CallStore(args ...string) (string, error) {
-	return ac.call(append([]string{"store"}, args...)...)
+func (ac *AdminClient) CallStore(ctx context.Context, args ...string) (string, error) {
+	return ac.call(ctx, append([]string{"store"}, args...)...)
 }
 
-func (ac *AdminClient) MaintenanceStore(action MaintenanceAction, args ...string) (string, error) {
-	return ac.call(append([]string{"store", "maintenance", string(action)}, args...)...)
+func (ac *AdminClient) MaintenanceStore(ctx context.Context, action MaintenanceAction, args ...string) (string, error) {
+	return ac.call(ctx, append([]string{"store", "maintenance", string(action)}, args...)...)
 }
 
-func (ac *AdminClient) GetHMetaStatus() (status HMetaStatus, err error) {
+func (ac *AdminClient) GetHMetaStatus(ctx context.Context) (status HMetaStatus, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
 	namespace := ""
 	hmetaAddr := ""
 
@@ -98,7 +146,7 @@ func (ac *AdminClient) GetHMetaStatus() (status HMetaStatus, err error) {
 		hmetaAddr = fmt.Sprintf("%s:%d", svc.Name, constants.DefaultHMetaPort.ContainerPort)
 	}
 
-	output, err := ac.executor.AccessServiceProxy(namespace, hmetaAddr, "nodes")
+	output, err := ac.executor.AccessServiceProxyWithContext(ctx, namespace, hmetaAddr, "nodes")
 	if err != nil {
 		err = fmt.Errorf("failed to get HMeta status: %w", err)
 
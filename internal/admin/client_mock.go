@@ -1,6 +1,7 @@
 package admin
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/go-logr/logr"
@@ -12,21 +13,21 @@ type mockAdminClient struct {
 	hdb *hapi.HStreamDB
 }
 
-func (ac *mockAdminClient) CallServer(args ...string) (string, error) {
+func (ac *mockAdminClient) CallServer(_ context.Context, args ...string) (string, error) {
 	panic("unimplemented")
 }
 
-func (ac *mockAdminClient) CallStore(args ...string) (string, error) {
+func (ac *mockAdminClient) CallStore(_ context.Context, args ...string) (string, error) {
 	panic("unimplemented")
 }
 
 // MaintenanceHStore implements HAdminClient.
 
-func (*mockAdminClient) MaintenanceStore(action MaintenanceAction, args ...string) (string, error) {
+func (*mockAdminClient) MaintenanceStore(_ context.Context, action MaintenanceAction, args ...string) (string, error) {
 	panic("unimplemented")
 }
 
-func (ac *mockAdminClient) GetHMetaStatus() (status HMetaStatus, err error) {
+func (ac *mockAdminClient) GetHMetaStatus(context.Context) (status HMetaStatus, err error) {
 	for i := 0; i < int(ac.hdb.Spec.HMeta.Replicas); i++ {
 		status.Nodes[fmt.Sprint("nodeId-", i)] = HMetaNode{
 			Reachable: true,
@@ -48,6 +49,8 @@ func (m *mockAdminClientProvider) GetAdminClient(hdb *hapi.HStreamDB) IAdminClie
 	return m.client
 }
 
+func (m *mockAdminClientProvider) Invalidate(string, string) {}
+
 // NewMockAdminClientProvider generates a client provider for talking to real hStream.
 func NewMockAdminClientProvider(*rest.Config, logr.Logger) AdminClientProvider {
 	return &mockAdminClientProvider{
@@ -0,0 +1,143 @@
+package admin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-logr/logr"
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"github.com/hstreamdb/hstream-operator/mock"
+	"github.com/hstreamdb/hstream-operator/pkg/executor"
+	"github.com/hstreamdb/hstream-operator/pkg/selector"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// fakeExecutor returns the result or error configured for each pod.
+type fakeExecutor struct {
+	errs  map[string]error
+	pods  []string
+	proxy []byte
+}
+
+func (f *fakeExecutor) RunCommandInPodWithContext(_ context.Context, podName, _ string, _ executor.Command) (*executor.Result, error) {
+	f.pods = append(f.pods, podName)
+	if err := f.errs[podName]; err != nil {
+		return &executor.Result{}, err
+	}
+	return &executor.Result{Stdout: "ok from " + podName, Stderr: "warning"}, nil
+}
+
+func (f *fakeExecutor) AccessServiceProxyWithContext(context.Context, string, string, string) ([]byte, error) {
+	return f.proxy, nil
+}
+
+var _ = Describe("admin/client", func() {
+	var hdb *hapi.HStreamDB
+	var exec *fakeExecutor
+	var client *AdminClient
+
+	newAdminPod := func(name string, ready bool) *corev1.Pod {
+		status := corev1.ConditionTrue
+		if !ready {
+			status = corev1.ConditionFalse
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: hdb.Namespace,
+				Labels: map[string]string{
+					hapi.InstanceKey:  hdb.Name,
+					hapi.ComponentKey: string(hapi.ComponentTypeAdminServer),
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		hdb = mock.CreateDefaultCR()
+		exec = &fakeExecutor{errs: map[string]error{}}
+		client = &AdminClient{
+			hdb:      hdb,
+			executor: exec,
+			log:      logr.Discard(),
+		}
+	})
+
+	It("should return an error if no admin pod is ready", func() {
+		client.selector = selector.NewSelector(fake.NewSimpleClientset(newAdminPod("admin-0", false)))
+
+		_, err := client.CallStore(context.TODO(), "status")
+		Expect(errors.Is(err, ErrNoReadyAdminPod)).To(BeTrue())
+		Expect(IsCommandFailed(err)).To(BeFalse())
+		Expect(exec.pods).To(BeEmpty())
+	})
+
+	It("should only run commands in ready pods", func() {
+		client.selector = selector.NewSelector(fake.NewSimpleClientset(
+			newAdminPod("admin-0", false),
+			newAdminPod("admin-1", true),
+		))
+
+		for i := 0; i < 3; i++ {
+			output, err := client.CallStore(context.TODO(), "status")
+			Expect(err).To(Succeed())
+			Expect(output).To(Equal("ok from admin-1"))
+		}
+		Expect(exec.pods).To(Equal([]string{"admin-1", "admin-1", "admin-1"}))
+	})
+
+	It("should rotate between ready pods", func() {
+		client.selector = selector.NewSelector(fake.NewSimpleClientset(
+			newAdminPod("admin-0", true),
+			newAdminPod("admin-1", true),
+		))
+
+		for i := 0; i < 2; i++ {
+			_, err := client.CallStore(context.TODO(), "status")
+			Expect(err).To(Succeed())
+		}
+		Expect(exec.pods).To(ConsistOf("admin-0", "admin-1"))
+	})
+
+	It("should fail over to the next pod if exec failed", func() {
+		client.selector = selector.NewSelector(fake.NewSimpleClientset(
+			newAdminPod("admin-0", true),
+			newAdminPod("admin-1", true),
+		))
+		exec.errs["admin-0"] = errors.New("error dialing backend")
+		exec.errs["admin-1"] = errors.New("error dialing backend")
+
+		_, err := client.CallStore(context.TODO(), "status")
+		Expect(err).To(MatchError(ContainSubstring("pod admin-0")))
+		Expect(err).To(MatchError(ContainSubstring("pod admin-1")))
+		Expect(exec.pods).To(HaveLen(2))
+
+		delete(exec.errs, "admin-1")
+		output, err := client.CallStore(context.TODO(), "status")
+		Expect(err).To(Succeed())
+		Expect(output).To(Equal("ok from admin-1"))
+	})
+
+	It("should not fail over if the command failed", func() {
+		client.selector = selector.NewSelector(fake.NewSimpleClientset(
+			newAdminPod("admin-0", true),
+			newAdminPod("admin-1", true),
+		))
+		exitErr := &executor.ExitError{Command: "hadmin store status", Result: &executor.Result{ExitCode: 1}}
+		exec.errs["admin-0"] = exitErr
+		exec.errs["admin-1"] = exitErr
+
+		_, err := client.CallStore(context.TODO(), "status")
+		Expect(err).To(Equal(exitErr))
+		Expect(IsCommandFailed(err)).To(BeTrue())
+		Expect(exec.pods).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,196 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"github.com/hstreamdb/hstream-operator/internal"
+	"github.com/hstreamdb/hstream-operator/pkg/constants"
+	jsoniter "github.com/json-iterator/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	sendAdminCommandMethod = "/hstream.server.HStreamApi/SendAdminCommand"
+
+	// defaultGRPCTimeout bounds the calls whose context has no deadline.
+	defaultGRPCTimeout = 30 * time.Second
+)
+
+// AdminCommandResult is the result of an admin command sent to HServer.
+type AdminCommandResult struct {
+	// Type is one of plain, table or error.
+	Type    string              `json:"type"`
+	Content jsoniter.RawMessage `json:"content"`
+}
+
+// CommandError is returned when HServer reports that an admin command failed.
+type CommandError struct {
+	Command string
+	Message string
+}
+
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("admin command %q failed: %s", e.Command, e.Message)
+}
+
+// GRPCAdminClient sends the server commands to HServer over gRPC. LogDevice
+// only exposes its admin API over Thrift, so the store commands and the other
+// calls are delegated to the fallback client.
+type GRPCAdminClient struct {
+	hdb      *hapi.HStreamDB
+	address  string
+	fallback IAdminClient
+	log      logr.Logger
+
+	// conn is dialed on the first call and reused by the later calls, it
+	// reconnects by itself when HServer restarts. calls tracks the calls in
+	// flight on conn, which is only closed once they have finished.
+	mu    sync.Mutex
+	conn  *grpc.ClientConn
+	calls *sync.WaitGroup
+}
+
+// NewGRPCAdminClient generates a gRPC admin client for a hStream, the fallback
+// client is used when HServer is unavailable over gRPC.
+func NewGRPCAdminClient(hdb *hapi.HStreamDB, fallback IAdminClient, log logr.Logger) *GRPCAdminClient {
+	svc := internal.GetHeadlessService(hdb, hapi.ComponentTypeHServer)
+
+	return &GRPCAdminClient{
+		hdb:      hdb,
+		address:  fmt.Sprintf("%s.%s:%d", svc.Name, hdb.Namespace, constants.DefaultHServerPort.ContainerPort),
+		fallback: fallback,
+		log: log.WithValues("namespace", hdb.Namespace).
+			WithValues("instance", hdb.Name),
+	}
+}
+
+// SendAdminCommand sends an admin command to HServer and returns its result.
+func (ac *GRPCAdminClient) SendAdminCommand(ctx context.Context, command string) (result AdminCommandResult, err error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultGRPCTimeout)
+		defer cancel()
+	}
+
+	conn, done, err := ac.getConn()
+	if err != nil {
+		return result, fmt.Errorf("failed to connect to HServer %s: %w", ac.address, err)
+	}
+	defer done()
+
+	resp := &adminCommandResponse{}
+	if err = conn.Invoke(ctx, sendAdminCommandMethod, &adminCommandRequest{Command: command}, resp,
+		grpc.ForceCodec(adminCodec{})); err != nil {
+		return result, err
+	}
+
+	if err = json.Unmarshal([]byte(resp.Result), &result); err != nil {
+		return result, fmt.Errorf("failed to unmarshal result of admin command %q: %w", command, err)
+	}
+	if result.Type == "error" {
+		return result, &CommandError{Command: command, Message: string(result.Content)}
+	}
+	return result, nil
+}
+
+// getConn returns the connection to HServer, done must be called once the call
+// on it has finished.
+func (ac *GRPCAdminClient) getConn() (conn *grpc.ClientConn, done func(), err error) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	if ac.conn == nil {
+		conn, err := grpc.Dial(ac.address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return nil, nil, err
+		}
+		ac.conn = conn
+		ac.calls = &sync.WaitGroup{}
+	}
+	ac.calls.Add(1)
+	return ac.conn, ac.calls.Done, nil
+}
+
+// Close closes the connection to HServer once the calls in flight have
+// finished, the client dials again if it is used afterwards.
+func (ac *GRPCAdminClient) Close() error {
+	ac.mu.Lock()
+	conn, calls := ac.conn, ac.calls
+	ac.conn, ac.calls = nil, nil
+	ac.mu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+	calls.Wait()
+	return conn.Close()
+}
+
+// CallServer sends the hadmin server command over gRPC and returns the raw
+// result. It falls back to exec if HServer cannot be reached.
+func (ac *GRPCAdminClient) CallServer(ctx context.Context, args ...string) (string, error) {
+	command := joinAdminArgs(args)
+	result, err := ac.SendAdminCommand(ctx, command)
+	if err == nil {
+		return string(result.Content), nil
+	}
+
+	if code := status.Code(err); code == codes.Unavailable || code == codes.Unimplemented {
+		ac.log.Info("HServer is unavailable over gRPC, fall back to exec", "command", command, "error", err.Error())
+		return ac.fallback.CallServer(ctx, args...)
+	}
+	return "", err
+}
+
+// joinAdminArgs joins the arguments into the command line parsed by HServer,
+// quoting those which would otherwise be split, such as SQL statements, in the
+// way of a POSIX shell, so that HServer runs the same command as hadmin.
+func joinAdminArgs(args []string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg != "" && !strings.ContainsAny(arg, " \t\n\r'\"\\$`|&;<>()*?[]#~!{}") {
+			quoted = append(quoted, arg)
+			continue
+		}
+		quoted = append(quoted, "'"+strings.ReplaceAll(arg, "'", `'\''`)+"'")
+	}
+	return strings.Join(quoted, " ")
+}
+
+// CallStore call hadmin store command with the fallback client.
+func (ac *GRPCAdminClient) CallStore(ctx context.Context, args ...string) (string, error) {
+	return ac.fallback.CallStore(ctx, args...)
+}
+
+func (ac *GRPCAdminClient) MaintenanceStore(ctx context.Context, action MaintenanceAction, args ...string) (string, error) {
+	return ac.fallback.MaintenanceStore(ctx, action, args...)
+}
+
+func (ac *GRPCAdminClient) GetHMetaStatus(ctx context.Context) (HMetaStatus, error) {
+	return ac.fallback.GetHMetaStatus(ctx)
+}
@@ -0,0 +1,154 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/go-logr/logr"
+	"github.com/hstreamdb/hstream-operator/mock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+var _ = Describe("admin/grpc", func() {
+	It("should encode admin command messages", func() {
+		data, err := adminCodec{}.Marshal(&adminCommandRequest{Command: "server status"})
+		Expect(err).To(Succeed())
+
+		req := &adminCommandRequest{}
+		Expect(adminCodec{}.Unmarshal(data, req)).To(Succeed())
+		Expect(req.Command).To(Equal("server status"))
+	})
+
+	Context("with HServer", func() {
+		var server *grpc.Server
+		var client *GRPCAdminClient
+		var fallback *recordingAdminClient
+		var received string
+
+		BeforeEach(func() {
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).To(Succeed())
+
+			server = grpc.NewServer(
+				grpc.ForceServerCodec(adminCodec{}),
+				grpc.UnknownServiceHandler(func(_ interface{}, stream grpc.ServerStream) error {
+					req := &adminCommandRequest{}
+					if err := stream.RecvMsg(req); err != nil {
+						return err
+					}
+					received = req.Command
+					if req.Command == "bad" {
+						return stream.SendMsg(&adminCommandResponse{Result: `{"type":"error","content":"unknown command"}`})
+					}
+					return stream.SendMsg(&adminCommandResponse{Result: `{"type":"plain","content":"done"}`})
+				}),
+			)
+			go func() {
+				_ = server.Serve(lis)
+			}()
+
+			fallback = &recordingAdminClient{}
+			client = NewGRPCAdminClient(mock.CreateDefaultCR(), fallback, logr.Discard())
+			client.address = lis.Addr().String()
+		})
+
+		AfterEach(func() {
+			_ = client.Close()
+			server.Stop()
+		})
+
+		It("should send server commands over gRPC", func() {
+			output, err := client.CallServer(context.TODO(), "status")
+			Expect(err).To(Succeed())
+			Expect(output).To(Equal(`"done"`))
+			Expect(received).To(Equal("status"))
+			Expect(fallback.calls).To(BeEmpty())
+		})
+
+		It("should reuse the connection to HServer", func() {
+			_, err := client.CallServer(context.TODO(), "stream", "list")
+			Expect(err).To(Succeed())
+			conn := client.conn
+			Expect(conn).NotTo(BeNil())
+
+			_, err = client.CallServer(context.TODO(), "status")
+			Expect(err).To(Succeed())
+			Expect(client.conn).To(BeIdenticalTo(conn))
+
+			Expect(client.Close()).To(Succeed())
+			Expect(client.conn).To(BeNil())
+		})
+
+		It("should quote the arguments which would be split by HServer", func() {
+			_, err := client.CallServer(context.TODO(), "sql", "SELECT * FROM s WHERE name = 'a b';")
+			Expect(err).To(Succeed())
+			Expect(received).To(Equal(`sql 'SELECT * FROM s WHERE name = '\''a b'\'';'`))
+		})
+
+		It("should close the connection once the calls in flight have finished", func() {
+			conn, done, err := client.getConn()
+			Expect(err).To(Succeed())
+
+			closed := make(chan error)
+			go func() {
+				closed <- client.Close()
+			}()
+			Consistently(closed).ShouldNot(Receive())
+			Expect(conn.GetState()).NotTo(Equal(connectivity.Shutdown))
+
+			done()
+			Eventually(closed).Should(Receive(BeNil()))
+			Expect(conn.GetState()).To(Equal(connectivity.Shutdown))
+		})
+
+		It("should return errors reported by HServer", func() {
+			_, err := client.CallServer(context.TODO(), "bad")
+			Expect(err).To(MatchError(ContainSubstring("unknown command")))
+			Expect(IsCommandFailed(err)).To(BeTrue())
+			Expect(fallback.calls).To(BeEmpty())
+		})
+
+		It("should fall back to exec if HServer is unavailable", func() {
+			server.Stop()
+
+			output, err := client.CallServer(context.TODO(), "init")
+			Expect(err).To(Succeed())
+			Expect(output).To(Equal("exec"))
+			Expect(fallback.calls).To(Equal([]string{"server"}))
+		})
+
+		It("should send store commands with the fallback client", func() {
+			_, err := client.CallStore(context.TODO(), "status")
+			Expect(err).To(Succeed())
+			Expect(fallback.calls).To(Equal([]string{"store"}))
+		})
+	})
+})
+
+type recordingAdminClient struct {
+	calls []string
+}
+
+func (c *recordingAdminClient) CallServer(context.Context, ...string) (string, error) {
+	c.calls = append(c.calls, "server")
+	return "exec", nil
+}
+
+func (c *recordingAdminClient) CallStore(context.Context, ...string) (string, error) {
+	c.calls = append(c.calls, "store")
+	return "exec", nil
+}
+
+func (c *recordingAdminClient) MaintenanceStore(context.Context, MaintenanceAction, ...string) (string, error) {
+	c.calls = append(c.calls, "maintenance")
+	return "exec", nil
+}
+
+func (c *recordingAdminClient) GetHMetaStatus(context.Context) (HMetaStatus, error) {
+	c.calls = append(c.calls, "hmeta")
+	return HMetaStatus{}, errors.New("unimplemented")
+}
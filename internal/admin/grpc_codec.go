@@ -0,0 +1,99 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// adminCommandRequest mirrors hstream.server.AdminCommandRequest.
+type adminCommandRequest struct {
+	Command string
+}
+
+// adminCommandResponse mirrors hstream.server.AdminCommandResponse.
+type adminCommandResponse struct {
+	Result string
+}
+
+// adminCodec encodes the admin command messages in the protobuf wire format.
+// Both messages only have a string field numbered 1, so that they are encoded
+// by hand instead of depending on the generated code of the HStream protos.
+type adminCodec struct{}
+
+func (adminCodec) Name() string {
+	return "proto"
+}
+
+func (adminCodec) Marshal(v interface{}) ([]byte, error) {
+	var field string
+	switch m := v.(type) {
+	case *adminCommandRequest:
+		field = m.Command
+	case *adminCommandResponse:
+		field = m.Result
+	default:
+		return nil, fmt.Errorf("unsupported message type %T", v)
+	}
+
+	var b []byte
+	if field != "" {
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendString(b, field)
+	}
+	return b, nil
+}
+
+func (adminCodec) Unmarshal(data []byte, v interface{}) error {
+	var field *string
+	switch m := v.(type) {
+	case *adminCommandRequest:
+		field = &m.Command
+	case *adminCommandResponse:
+		field = &m.Result
+	default:
+		return fmt.Errorf("unsupported message type %T", v)
+	}
+
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		if num == 1 && typ == protowire.BytesType {
+			s, n := protowire.ConsumeString(data)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			*field = s
+			data = data[n:]
+			continue
+		}
+
+		// skip unknown fields
+		n = protowire.ConsumeFieldValue(num, typ, data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"io"
+	"sync"
+
+	"github.com/go-logr/logr"
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"github.com/hstreamdb/hstream-operator/pkg/executor"
+	"github.com/hstreamdb/hstream-operator/pkg/selector"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ProviderOption configures the admin client provider.
+type ProviderOption func(*adminClientProvider)
+
+// WithMode sets how the admin commands are sent, exec by default.
+func WithMode(mode AdminClientMode) ProviderOption {
+	return func(p *adminClientProvider) {
+		p.mode = mode
+	}
+}
+
+// WithPodReader lists the admin server pods with the reader, e.g. the client
+// of the manager which is served from the informer cache. The pods are listed
+// from the API server by default.
+func WithPodReader(reader client.Reader) ProviderOption {
+	return func(p *adminClientProvider) {
+		p.podReader = reader
+	}
+}
+
+// WithExecutorOptions configures the executor running hadmin in pods.
+func WithExecutorOptions(opts ...executor.Option) ProviderOption {
+	return func(p *adminClientProvider) {
+		p.executorOptions = append(p.executorOptions, opts...)
+	}
+}
+
+type adminClientProvider struct {
+	// log defines the logger for the admin client.
+	log logr.Logger
+
+	// mode defines how the admin commands are sent.
+	mode AdminClientMode
+
+	podReader       client.Reader
+	executorOptions []executor.Option
+
+	// executor and selector are shared by all clients.
+	executor executor.Executor
+	selector *selector.Selector
+
+	mu      sync.Mutex
+	clients map[types.NamespacedName]cachedAdminClient
+}
+
+// cachedAdminClient is a client built for a generation of an HStreamDB.
+type cachedAdminClient struct {
+	uid        types.UID
+	generation int64
+	client     IAdminClient
+}
+
+// GetAdminClient returns the cached client of the HStreamDB, a new client is
+// built when the spec of the HStreamDB changes.
+func (p *adminClientProvider) GetAdminClient(hdb *hapi.HStreamDB) IAdminClient {
+	key := types.NamespacedName{Namespace: hdb.Namespace, Name: hdb.Name}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if cached, ok := p.clients[key]; ok {
+		if cached.uid == hdb.UID && cached.generation == hdb.Generation {
+			return cached.client
+		}
+		go closeAdminClient(cached.client)
+	}
+
+	// The client keeps its own copy, since the HStreamDB is modified by the
+	// reconciler.
+	hdb = hdb.DeepCopy()
+	var c IAdminClient = NewAdminClient(hdb, p.executor, p.selector, p.log)
+	if p.mode == AdminClientModeGRPC {
+		c = NewGRPCAdminClient(hdb, c, p.log)
+	}
+	p.clients[key] = cachedAdminClient{uid: hdb.UID, generation: hdb.Generation, client: c}
+	return c
+}
+
+func (p *adminClientProvider) Invalidate(namespace, name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+	if cached, ok := p.clients[key]; ok {
+		go closeAdminClient(cached.client)
+	}
+	delete(p.clients, key)
+}
+
+// closeAdminClient releases the connections held by a client which is no
+// longer cached. It waits for the calls in flight, so it runs in the
+// background instead of blocking the provider.
+func closeAdminClient(c IAdminClient) {
+	if closer, ok := c.(io.Closer); ok {
+		_ = closer.Close()
+	}
+}
+
+// NewAdminClientProvider generates a provider which caches an admin client
+// per HStreamDB.
+func NewAdminClientProvider(restConfig *rest.Config, log logr.Logger, opts ...ProviderOption) (AdminClientProvider, error) {
+	p := &adminClientProvider{
+		log:     log.WithName("Admin Client"),
+		mode:    AdminClientModeExec,
+		clients: make(map[types.NamespacedName]cachedAdminClient),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	e, err := executor.NewRemoteExecutor(restConfig, p.executorOptions...)
+	if err != nil {
+		return nil, err
+	}
+	p.executor = e
+
+	if p.podReader != nil {
+		p.selector = selector.NewCachedSelector(p.podReader)
+	} else {
+		p.selector = selector.NewSelector(e.Clientset)
+	}
+	return p, nil
+}
@@ -0,0 +1,90 @@
+package admin
+
+import (
+	"github.com/go-logr/logr"
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"github.com/hstreamdb/hstream-operator/mock"
+	"github.com/hstreamdb/hstream-operator/pkg/selector"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/connectivity"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var _ = Describe("admin/provider", func() {
+	var provider *adminClientProvider
+
+	BeforeEach(func() {
+		provider = &adminClientProvider{
+			log:      logr.Discard(),
+			mode:     AdminClientModeExec,
+			executor: &fakeExecutor{},
+			selector: selector.NewSelector(fake.NewSimpleClientset()),
+			clients:  make(map[types.NamespacedName]cachedAdminClient),
+		}
+	})
+
+	It("should cache the client of the same generation", func() {
+		hdb := mock.CreateDefaultCR()
+		hdb.UID = "uid-1"
+		hdb.Generation = 1
+
+		client := provider.GetAdminClient(hdb)
+		Expect(provider.GetAdminClient(hdb)).To(BeIdenticalTo(client))
+
+		// the status changes don't bump the generation
+		hdb.Status.HMeta.Nodes = append(hdb.Status.HMeta.Nodes, hapi.HMetaNode{})
+		Expect(provider.GetAdminClient(hdb)).To(BeIdenticalTo(client))
+	})
+
+	It("should rebuild the client if the spec changed", func() {
+		hdb := mock.CreateDefaultCR()
+		hdb.UID = "uid-1"
+		hdb.Generation = 1
+		client := provider.GetAdminClient(hdb)
+
+		hdb.Generation = 2
+		updated := provider.GetAdminClient(hdb)
+		Expect(updated).NotTo(BeIdenticalTo(client))
+		Expect(updated.(*AdminClient).hdb.Generation).To(BeEquivalentTo(2))
+
+		// recreated with the same name
+		hdb.UID = "uid-2"
+		Expect(provider.GetAdminClient(hdb)).NotTo(BeIdenticalTo(updated))
+	})
+
+	It("should keep a copy of the HStreamDB", func() {
+		hdb := mock.CreateDefaultCR()
+		client := provider.GetAdminClient(hdb).(*AdminClient)
+
+		hdb.Spec.HServer.Image = "hstreamdb/hstream:changed"
+		Expect(client.hdb.Spec.HServer.Image).NotTo(Equal(hdb.Spec.HServer.Image))
+	})
+
+	It("should drop the client once invalidated", func() {
+		hdb := mock.CreateDefaultCR()
+		client := provider.GetAdminClient(hdb)
+
+		provider.Invalidate(hdb.Namespace, hdb.Name)
+		Expect(provider.clients).To(BeEmpty())
+		Expect(provider.GetAdminClient(hdb)).NotTo(BeIdenticalTo(client))
+	})
+
+	It("should wrap the client in grpc mode", func() {
+		provider.mode = AdminClientModeGRPC
+		Expect(provider.GetAdminClient(mock.CreateDefaultCR())).To(BeAssignableToTypeOf(&GRPCAdminClient{}))
+	})
+
+	It("should close the connection of a dropped grpc client", func() {
+		provider.mode = AdminClientModeGRPC
+		hdb := mock.CreateDefaultCR()
+		client := provider.GetAdminClient(hdb).(*GRPCAdminClient)
+		conn, done, err := client.getConn()
+		Expect(err).To(Succeed())
+		done()
+
+		provider.Invalidate(hdb.Namespace, hdb.Name)
+		Eventually(conn.GetState).Should(Equal(connectivity.Shutdown))
+	})
+})
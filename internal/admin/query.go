@@ -0,0 +1,266 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Query is a row of `hadmin server query list`.
+type Query struct {
+	ID     string
+	Status string
+	// NodeID is zero if HServer does not report the node running the query.
+	NodeID int32
+	SQL    string
+}
+
+// IsRunning reports whether the task of the query is running.
+func (q Query) IsRunning() bool {
+	return normalizeTaskStatus(q.Status) == "RUNNING"
+}
+
+// IsAborted reports whether the task of the query stopped on an error.
+func (q Query) IsAborted() bool {
+	return strings.Contains(normalizeTaskStatus(q.Status), "ABORT")
+}
+
+// View is a row of `hadmin server view list`.
+type View struct {
+	Name string
+	// QueryID is the ID of the query materializing the view.
+	QueryID string
+	Status  string
+	NodeID  int32
+	SQL     string
+}
+
+// HasSQL reports whether the view materializes the statement, a view whose
+// statement is not reported is assumed to.
+func (v View) HasSQL(sql string) bool {
+	return v.SQL == "" || normalizeSQL(v.SQL) == normalizeSQL(sql)
+}
+
+// Query returns the query materializing the view.
+func (v View) Query() Query {
+	return Query{ID: v.QueryID, Status: v.Status, NodeID: v.NodeID, SQL: v.SQL}
+}
+
+// normalizeTaskStatus makes "Running" and "TASK_RUNNING" comparable.
+func normalizeTaskStatus(status string) string {
+	return strings.TrimPrefix(strings.ToUpper(status), "TASK_")
+}
+
+// ExecuteSQL runs a statement with `hadmin server sql`.
+func (c TypedAdminClient) ExecuteSQL(ctx context.Context, sql string) (string, error) {
+	return c.CallServer(ctx, "sql", sql)
+}
+
+func (c TypedAdminClient) ListQueries(ctx context.Context) ([]Query, error) {
+	output, err := c.CallServer(ctx, "query", "list")
+	if err != nil {
+		return nil, err
+	}
+	return ParseQueries(output)
+}
+
+// GetQuery returns the query with the ID, or nil if there is no such query.
+func (c TypedAdminClient) GetQuery(ctx context.Context, id string) (*Query, error) {
+	queries, err := c.ListQueries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range queries {
+		if queries[i].ID == id {
+			return &queries[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// FindQueryBySQL returns the last query running the statement, or nil if
+// there is no such query.
+func (c TypedAdminClient) FindQueryBySQL(ctx context.Context, sql string) (*Query, error) {
+	queries, err := c.ListQueries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sql = normalizeSQL(sql)
+	for i := len(queries) - 1; i >= 0; i-- {
+		if normalizeSQL(queries[i].SQL) == sql {
+			return &queries[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateQuery submits the statement of a continuous query, and returns the
+// query reported by HServer, or found by its statement if HServer does not
+// print the created query.
+func (c TypedAdminClient) CreateQuery(ctx context.Context, sql string) (*Query, error) {
+	output, err := c.ExecuteSQL(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	if queries, err := ParseQueries(output); err == nil && len(queries) == 1 {
+		return &queries[0], nil
+	}
+
+	query, err := c.FindQueryBySQL(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	if query == nil {
+		return nil, fmt.Errorf("query not found after submitting: %s", strings.TrimSpace(output))
+	}
+	return query, nil
+}
+
+func (c TypedAdminClient) TerminateQuery(ctx context.Context, id string) error {
+	_, err := c.CallServer(ctx, "query", "terminate", id)
+	return err
+}
+
+func (c TypedAdminClient) ResumeQuery(ctx context.Context, id string) error {
+	_, err := c.CallServer(ctx, "query", "resume", id)
+	return err
+}
+
+func (c TypedAdminClient) DeleteQuery(ctx context.Context, id string) error {
+	_, err := c.CallServer(ctx, "query", "delete", id)
+	return err
+}
+
+// RemoveQuery terminates the query if it is still running, then deletes it.
+func (c TypedAdminClient) RemoveQuery(ctx context.Context, query *Query) error {
+	if query.IsRunning() {
+		if err := c.TerminateQuery(ctx, query.ID); err != nil {
+			return fmt.Errorf("failed to terminate query %s: %w", query.ID, err)
+		}
+	}
+	if err := c.DeleteQuery(ctx, query.ID); err != nil {
+		return fmt.Errorf("failed to delete query %s: %w", query.ID, err)
+	}
+	return nil
+}
+
+func (c TypedAdminClient) ListViews(ctx context.Context) ([]View, error) {
+	output, err := c.CallServer(ctx, "view", "list")
+	if err != nil {
+		return nil, err
+	}
+	return ParseViews(output)
+}
+
+// GetView returns the view with the name, or nil if there is no such view.
+func (c TypedAdminClient) GetView(ctx context.Context, name string) (*View, error) {
+	views, err := c.ListViews(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range views {
+		if views[i].Name == name {
+			return &views[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateView materializes the SELECT statement in a view with the name.
+func (c TypedAdminClient) CreateView(ctx context.Context, name, sql string) error {
+	_, err := c.ExecuteSQL(ctx, fmt.Sprintf("CREATE VIEW %s AS %s;", name, strings.TrimRight(strings.TrimSpace(sql), ";")))
+	return err
+}
+
+func (c TypedAdminClient) DeleteView(ctx context.Context, name string) error {
+	_, err := c.CallServer(ctx, "view", "delete", name)
+	return err
+}
+
+// ParseQueries parses the output of `hadmin server query list`.
+func ParseQueries(output string) ([]Query, error) {
+	table, err := ParseTable(output)
+	if err != nil {
+		return nil, err
+	}
+	if err = table.requireColumns("QUERY ID", "STATUS"); err != nil {
+		return nil, err
+	}
+
+	queries := make([]Query, 0, len(table.Rows))
+	for _, row := range table.Rows {
+		nodeID, err := parseNodeID(table, row)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, Query{
+			ID:     table.Get(row, "QUERY ID"),
+			Status: table.Get(row, "STATUS"),
+			NodeID: nodeID,
+			SQL:    table.Get(row, "SQL"),
+		})
+	}
+	return queries, nil
+}
+
+// ParseViews parses the output of `hadmin server view list`.
+func ParseViews(output string) ([]View, error) {
+	table, err := ParseTable(output)
+	if err != nil {
+		return nil, err
+	}
+	if err = table.requireColumns("VIEW NAME", "STATUS"); err != nil {
+		return nil, err
+	}
+
+	views := make([]View, 0, len(table.Rows))
+	for _, row := range table.Rows {
+		nodeID, err := parseNodeID(table, row)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, View{
+			Name:    table.Get(row, "VIEW NAME"),
+			QueryID: table.Get(row, "QUERY ID"),
+			Status:  table.Get(row, "STATUS"),
+			NodeID:  nodeID,
+			SQL:     table.Get(row, "SQL"),
+		})
+	}
+	return views, nil
+}
+
+func parseNodeID(table Table, row []string) (int32, error) {
+	value := table.Get(row, "NODE ID")
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("column NODE ID: invalid integer %q", value)
+	}
+	return int32(id), nil
+}
+
+// normalizeSQL makes the statements comparable regardless of the whitespaces
+// and the trailing semicolon.
+func normalizeSQL(sql string) string {
+	return strings.TrimRight(strings.Join(strings.Fields(sql), " "), ";")
+}
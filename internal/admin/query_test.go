@@ -0,0 +1,76 @@
+package admin
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("admin/query", func() {
+	It("should parse queries", func() {
+		queries, err := ParseQueries(readFixture("query_list.txt"))
+		Expect(err).To(Succeed())
+		Expect(queries).To(HaveLen(2))
+		Expect(queries[0]).To(Equal(Query{
+			ID:     "cli_generated_a1b2c",
+			Status: "TASK_RUNNING",
+			NodeID: 100,
+			SQL:    "CREATE STREAM large_orders AS SELECT * FROM orders WHERE amount > 100;",
+		}))
+		Expect(queries[1].SQL).To(Equal("CREATE STREAM full_names AS SELECT first_name || ' ' || last_name AS name FROM users;"))
+		Expect(queries[0].IsRunning()).To(BeTrue())
+		Expect(queries[1].IsRunning()).To(BeFalse())
+		Expect(queries[1].IsAborted()).To(BeTrue())
+	})
+
+	It("should parse views", func() {
+		views, err := ParseViews(readFixture("view_list.txt"))
+		Expect(err).To(Succeed())
+		Expect(views).To(Equal([]View{{
+			Name:    "orders_by_user",
+			QueryID: "cli_generated_g5h6i",
+			Status:  "Running",
+			NodeID:  100,
+			SQL:     "SELECT user_id, COUNT(*) AS orders FROM orders GROUP BY user_id;",
+		}}))
+		Expect(views[0].Query().IsRunning()).To(BeTrue())
+	})
+
+	It("should find the created query by its statement", func() {
+		client := NewTypedAdminClient(&sqlAdminClient{queries: readFixture("query_list.txt")})
+
+		query, err := client.CreateQuery(context.TODO(),
+			"CREATE STREAM large_orders AS\n  SELECT * FROM orders WHERE amount > 100")
+		Expect(err).To(Succeed())
+		Expect(query.ID).To(Equal("cli_generated_a1b2c"))
+
+		_, err = client.CreateQuery(context.TODO(), "CREATE STREAM unknown AS SELECT * FROM orders;")
+		Expect(err).To(MatchError(ContainSubstring("query not found")))
+	})
+
+	It("should create views", func() {
+		recorder := &argsAdminClient{}
+		client := NewTypedAdminClient(recorder)
+
+		Expect(client.CreateView(context.TODO(), "orders_by_user",
+			"SELECT user_id, COUNT(*) AS orders FROM orders GROUP BY user_id; ")).To(Succeed())
+		Expect(recorder.commands).To(Equal([]string{
+			"sql CREATE VIEW orders_by_user AS SELECT user_id, COUNT(*) AS orders FROM orders GROUP BY user_id;",
+		}))
+	})
+})
+
+// sqlAdminClient prints the queries and accepts any statement.
+type sqlAdminClient struct {
+	recordingAdminClient
+	queries string
+}
+
+func (c *sqlAdminClient) CallServer(_ context.Context, args ...string) (string, error) {
+	if strings.Join(args, " ") == "query list" {
+		return c.queries, nil
+	}
+	return "Done.", nil
+}
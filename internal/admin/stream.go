@@ -0,0 +1,135 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Stream is a row of `hadmin server stream list`.
+type Stream struct {
+	Name              string
+	ReplicationFactor int32
+	// BacklogDuration is zero if the stream uses the default of HServer.
+	BacklogDuration time.Duration
+	ShardCount      int32
+}
+
+// StreamOptions are the settings of a stream to create.
+type StreamOptions struct {
+	ReplicationFactor int32
+	// BacklogDuration is left to the default of HServer if zero.
+	BacklogDuration time.Duration
+	ShardCount      int32
+}
+
+func (c TypedAdminClient) ListStreams(ctx context.Context) ([]Stream, error) {
+	output, err := c.CallServer(ctx, "stream", "list")
+	if err != nil {
+		return nil, err
+	}
+	return ParseStreams(output)
+}
+
+// GetStream returns the stream with the name, or nil if there is no such stream.
+func (c TypedAdminClient) GetStream(ctx context.Context, name string) (*Stream, error) {
+	streams, err := c.ListStreams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range streams {
+		if streams[i].Name == name {
+			return &streams[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (c TypedAdminClient) CreateStream(ctx context.Context, name string, opts StreamOptions) error {
+	args := []string{"stream", "create", name,
+		"--replication-factor", strconv.Itoa(int(opts.ReplicationFactor)),
+		"--shards", strconv.Itoa(int(opts.ShardCount)),
+	}
+	if opts.BacklogDuration > 0 {
+		args = append(args, "--backlog-duration", strconv.FormatInt(int64(opts.BacklogDuration/time.Second), 10))
+	}
+	_, err := c.CallServer(ctx, args...)
+	return err
+}
+
+func (c TypedAdminClient) DeleteStream(ctx context.Context, name string) error {
+	_, err := c.CallServer(ctx, "stream", "delete", name)
+	return err
+}
+
+// ParseStreams parses the output of `hadmin server stream list`.
+func ParseStreams(output string) ([]Stream, error) {
+	table, err := ParseTable(output)
+	if err != nil {
+		return nil, err
+	}
+	if err = table.requireColumns("STREAM NAME"); err != nil {
+		return nil, err
+	}
+
+	streams := make([]Stream, 0, len(table.Rows))
+	for _, row := range table.Rows {
+		stream := Stream{Name: table.Get(row, "STREAM NAME")}
+
+		replicas, err := table.GetInt(row, "REPLICATION FACTOR")
+		if err != nil {
+			return nil, err
+		}
+		stream.ReplicationFactor = int32(replicas)
+
+		if stream.BacklogDuration, err = parseSeconds(table.Get(row, "BACKLOG DURATION")); err != nil {
+			return nil, err
+		}
+
+		shards, err := table.GetInt(row, "SHARD COUNT")
+		if err != nil {
+			return nil, err
+		}
+		stream.ShardCount = int32(shards)
+
+		streams = append(streams, stream)
+	}
+	return streams, nil
+}
+
+var secondsRegexp = regexp.MustCompile(`^(\d+)\s*(s|sec|secs|seconds)?$`)
+
+// parseSeconds parses a duration printed in seconds, e.g. "86400" or
+// "86400 seconds".
+func parseSeconds(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	match := secondsRegexp.FindStringSubmatch(value)
+	if match == nil {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	seconds, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", value, err)
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
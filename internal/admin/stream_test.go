@@ -0,0 +1,63 @@
+package admin
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("admin/stream", func() {
+	It("should parse streams", func() {
+		streams, err := ParseStreams(readFixture("stream_list.txt"))
+		Expect(err).To(Succeed())
+		Expect(streams).To(Equal([]Stream{
+			{Name: "orders", ReplicationFactor: 3, BacklogDuration: 24 * time.Hour, ShardCount: 2},
+			{Name: "clicks", ReplicationFactor: 1, ShardCount: 1},
+		}))
+	})
+
+	It("should get a stream by name", func() {
+		client := NewTypedAdminClient(&fixtureAdminClient{server: readFixture("stream_list.txt")})
+
+		stream, err := client.GetStream(context.TODO(), "clicks")
+		Expect(err).To(Succeed())
+		Expect(stream.Name).To(Equal("clicks"))
+
+		stream, err = client.GetStream(context.TODO(), "missing")
+		Expect(err).To(Succeed())
+		Expect(stream).To(BeNil())
+	})
+
+	It("should create and delete streams", func() {
+		recorder := &argsAdminClient{}
+		client := NewTypedAdminClient(recorder)
+
+		Expect(client.CreateStream(context.TODO(), "orders", StreamOptions{
+			ReplicationFactor: 3,
+			BacklogDuration:   24 * time.Hour,
+			ShardCount:        2,
+		})).To(Succeed())
+		Expect(client.CreateStream(context.TODO(), "clicks", StreamOptions{ReplicationFactor: 1, ShardCount: 1})).To(Succeed())
+		Expect(client.DeleteStream(context.TODO(), "orders")).To(Succeed())
+
+		Expect(recorder.commands).To(Equal([]string{
+			"stream create orders --replication-factor 3 --shards 2 --backlog-duration 86400",
+			"stream create clicks --replication-factor 1 --shards 1",
+			"stream delete orders",
+		}))
+	})
+})
+
+// argsAdminClient records the arguments of the server commands.
+type argsAdminClient struct {
+	recordingAdminClient
+	commands []string
+}
+
+func (c *argsAdminClient) CallServer(_ context.Context, args ...string) (string, error) {
+	c.commands = append(c.commands, strings.Join(args, " "))
+	return "", nil
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// Subscription is a row of `hadmin server sub list`.
+type Subscription struct {
+	ID                string
+	StreamName        string
+	AckTimeoutSeconds int32
+	MaxUnackedRecords int32
+	// Consumers and Backlog are zero if HServer does not report them.
+	Consumers int32
+	Backlog   int64
+}
+
+// SubscriptionOptions are the settings of a subscription to create.
+type SubscriptionOptions struct {
+	StreamName        string
+	AckTimeoutSeconds int32
+	MaxUnackedRecords int32
+	// Offset is either "earliest" or "latest".
+	Offset string
+}
+
+func (c TypedAdminClient) ListSubscriptions(ctx context.Context) ([]Subscription, error) {
+	output, err := c.CallServer(ctx, "sub", "list")
+	if err != nil {
+		return nil, err
+	}
+	return ParseSubscriptions(output)
+}
+
+// GetSubscription returns the subscription with the ID, or nil if there is no
+// such subscription.
+func (c TypedAdminClient) GetSubscription(ctx context.Context, id string) (*Subscription, error) {
+	subscriptions, err := c.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range subscriptions {
+		if subscriptions[i].ID == id {
+			return &subscriptions[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (c TypedAdminClient) CreateSubscription(ctx context.Context, id string, opts SubscriptionOptions) error {
+	args := []string{"sub", "create", id,
+		"--stream", opts.StreamName,
+		"--ack-timeout", strconv.Itoa(int(opts.AckTimeoutSeconds)),
+		"--max-unacked-records", strconv.Itoa(int(opts.MaxUnackedRecords)),
+	}
+	if opts.Offset != "" {
+		args = append(args, "--offset", strings.ToLower(opts.Offset))
+	}
+	_, err := c.CallServer(ctx, args...)
+	return err
+}
+
+// DeleteSubscription deletes the subscription, force is required to delete a
+// subscription with active consumers.
+func (c TypedAdminClient) DeleteSubscription(ctx context.Context, id string, force bool) error {
+	args := []string{"sub", "delete", id}
+	if force {
+		args = append(args, "--force")
+	}
+	_, err := c.CallServer(ctx, args...)
+	return err
+}
+
+// ParseSubscriptions parses the output of `hadmin server sub list`.
+func ParseSubscriptions(output string) ([]Subscription, error) {
+	table, err := ParseTable(output)
+	if err != nil {
+		return nil, err
+	}
+	if err = table.requireColumns("SUBSCRIPTION ID", "STREAM NAME"); err != nil {
+		return nil, err
+	}
+
+	subscriptions := make([]Subscription, 0, len(table.Rows))
+	for _, row := range table.Rows {
+		subscription := Subscription{
+			ID:         table.Get(row, "SUBSCRIPTION ID"),
+			StreamName: table.Get(row, "STREAM NAME"),
+		}
+
+		ackTimeout, err := table.GetInt(row, "ACK TIMEOUT")
+		if err != nil {
+			return nil, err
+		}
+		subscription.AckTimeoutSeconds = int32(ackTimeout)
+
+		maxUnacked, err := table.GetInt(row, "MAX UNACKED RECORDS")
+		if err != nil {
+			return nil, err
+		}
+		subscription.MaxUnackedRecords = int32(maxUnacked)
+
+		if table.column("CONSUMERS") != -1 {
+			consumers, err := table.GetInt(row, "CONSUMERS")
+			if err != nil {
+				return nil, err
+			}
+			subscription.Consumers = int32(consumers)
+		}
+		if table.column("BACKLOG") != -1 {
+			backlog, err := table.GetInt(row, "BACKLOG")
+			if err != nil {
+				return nil, err
+			}
+			subscription.Backlog = int64(backlog)
+		}
+
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, nil
+}
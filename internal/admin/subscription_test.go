@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("admin/subscription", func() {
+	It("should parse subscriptions", func() {
+		subscriptions, err := ParseSubscriptions(readFixture("sub_list.txt"))
+		Expect(err).To(Succeed())
+		Expect(subscriptions).To(Equal([]Subscription{
+			{ID: "orders-billing", StreamName: "orders", AckTimeoutSeconds: 60, MaxUnackedRecords: 10000, Consumers: 2, Backlog: 1520},
+			{ID: "clicks-audit", StreamName: "clicks", AckTimeoutSeconds: 600, MaxUnackedRecords: 500},
+		}))
+	})
+
+	It("should parse subscriptions without consumers and backlog", func() {
+		subscriptions, err := ParseSubscriptions(`
+| Subscription ID | Stream Name | Ack Timeout | Max Unacked Records |
+| orders-billing  | orders      | 60          | 10000               |`)
+		Expect(err).To(Succeed())
+		Expect(subscriptions).To(Equal([]Subscription{
+			{ID: "orders-billing", StreamName: "orders", AckTimeoutSeconds: 60, MaxUnackedRecords: 10000},
+		}))
+	})
+
+	It("should create and delete subscriptions", func() {
+		recorder := &argsAdminClient{}
+		client := NewTypedAdminClient(recorder)
+
+		Expect(client.CreateSubscription(context.TODO(), "orders-billing", SubscriptionOptions{
+			StreamName:        "orders",
+			AckTimeoutSeconds: 60,
+			MaxUnackedRecords: 10000,
+			Offset:            "Earliest",
+		})).To(Succeed())
+		Expect(client.DeleteSubscription(context.TODO(), "orders-billing", false)).To(Succeed())
+		Expect(client.DeleteSubscription(context.TODO(), "clicks-audit", true)).To(Succeed())
+
+		Expect(recorder.commands).To(Equal([]string{
+			"sub create orders-billing --stream orders --ack-timeout 60 --max-unacked-records 10000 --offset earliest",
+			"sub delete orders-billing",
+			"sub delete clicks-audit --force",
+		}))
+	})
+})
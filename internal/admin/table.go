@@ -0,0 +1,184 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Table is a table printed by hadmin.
+type Table struct {
+	Headers []string   `json:"headers"`
+	Rows    [][]string `json:"rows"`
+}
+
+// ParseTable parses the output of hadmin, either a table drawn with '|' and
+// '+' borders, or the JSON results of HServer admin commands.
+func ParseTable(output string) (table Table, err error) {
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return table, errors.New("empty output")
+	}
+
+	if strings.HasPrefix(output, "{") {
+		return parseJSONTable(output)
+	}
+
+	// The columns are cut at the '+' of the border above the headers, since a
+	// cell, e.g. the SQL of a query, may hold '|' as well.
+	var border []int
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "+") && border == nil {
+			border = columnOffsets(line)
+			continue
+		}
+		if !strings.HasPrefix(line, "|") {
+			// borders and messages around the table
+			continue
+		}
+
+		cells, err := splitRow(line, border)
+		if err != nil {
+			return table, err
+		}
+		if table.Headers == nil {
+			table.Headers = cells
+			continue
+		}
+		if len(cells) != len(table.Headers) {
+			return table, fmt.Errorf("row %q has %d cells, expected %d", line, len(cells), len(table.Headers))
+		}
+		table.Rows = append(table.Rows, cells)
+	}
+
+	if table.Headers == nil {
+		return table, fmt.Errorf("no table found in output: %s", output)
+	}
+	return table, nil
+}
+
+// columnOffsets returns the offsets, in runes, of the '+' of a border line.
+func columnOffsets(border string) []int {
+	var offsets []int
+	for i, r := range []rune(border) {
+		if r == '+' {
+			offsets = append(offsets, i)
+		}
+	}
+	return offsets
+}
+
+// splitRow splits a row of the table at the column offsets taken from its
+// border, or at every '|' if the table has no border.
+func splitRow(line string, border []int) ([]string, error) {
+	var cells []string
+	if border == nil {
+		cells = strings.Split(strings.Trim(line, "|"), "|")
+	} else {
+		runes := []rune(line)
+		if len(runes) != border[len(border)-1]+1 {
+			return nil, fmt.Errorf("row %q is not aligned with the border of the table", line)
+		}
+		for i := 1; i < len(border); i++ {
+			if runes[border[i]] != '|' {
+				return nil, fmt.Errorf("row %q is not aligned with the border of the table", line)
+			}
+			cells = append(cells, string(runes[border[i-1]+1:border[i]]))
+		}
+	}
+
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells, nil
+}
+
+func parseJSONTable(output string) (table Table, err error) {
+	var result AdminCommandResult
+	if err = json.Unmarshal([]byte(output), &result); err == nil && result.Type != "" {
+		switch result.Type {
+		case "table":
+			err = json.Unmarshal(result.Content, &table)
+		case "error":
+			err = fmt.Errorf("admin command failed: %s", result.Content)
+		default:
+			err = fmt.Errorf("unexpected result type %q", result.Type)
+		}
+		return
+	}
+
+	err = json.Unmarshal([]byte(output), &table)
+	if err == nil && table.Headers == nil {
+		err = fmt.Errorf("no table found in output: %s", output)
+	}
+	return
+}
+
+// column returns the index of the column, the names are compared regardless
+// of the case, spaces, dots and underscores.
+func (t Table) column(name string) int {
+	name = normalizeHeader(name)
+	for i, header := range t.Headers {
+		if normalizeHeader(header) == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Get returns the cell of the row in the column, or empty if the table has no
+// such column.
+func (t Table) Get(row []string, name string) string {
+	if i := t.column(name); i != -1 && i < len(row) {
+		return row[i]
+	}
+	return ""
+}
+
+// GetInt returns the cell of the row in the column as an integer.
+func (t Table) GetInt(row []string, name string) (int, error) {
+	value := t.Get(row, name)
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("column %s: invalid integer %q", name, value)
+	}
+	return n, nil
+}
+
+// requireColumns makes sure the table has the columns, so that a change of
+// the output of hadmin is reported instead of returning empty fields.
+func (t Table) requireColumns(names ...string) error {
+	var missing []string
+	for _, name := range names {
+		if t.column(name) == -1 {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing columns %s in table with headers %s",
+			strings.Join(missing, ", "), strings.Join(t.Headers, ", "))
+	}
+	return nil
+}
+
+func normalizeHeader(header string) string {
+	return strings.NewReplacer(" ", "", ".", "", "_", "", "-", "").Replace(strings.ToUpper(header))
+}
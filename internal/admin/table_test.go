@@ -0,0 +1,37 @@
+package admin
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("admin/table", func() {
+	It("should split the rows at the columns of the border", func() {
+		table, err := ParseTable(`
++----+--------------+
+| ID |     SQL      |
++----+--------------+
+| 1  | SELECT a | b |
+| 2  | ||           |
++----+--------------+`)
+		Expect(err).To(Succeed())
+		Expect(table.Headers).To(Equal([]string{"ID", "SQL"}))
+		Expect(table.Rows).To(Equal([][]string{{"1", "SELECT a | b"}, {"2", "||"}}))
+	})
+
+	It("should reject rows which are not aligned with the border", func() {
+		_, err := ParseTable(`
++----+-----+
+| ID | SQL |
++----+-----+
+| 1  | SELECT 1; |
++----+-----+`)
+		Expect(err).To(MatchError(ContainSubstring("not aligned")))
+	})
+
+	It("should split the rows of a table without border at every pipe", func() {
+		table, err := ParseTable("| ID | NAME |\n| 1 | a |")
+		Expect(err).To(Succeed())
+		Expect(table.Rows).To(Equal([][]string{{"1", "a"}}))
+	})
+})
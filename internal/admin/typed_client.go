@@ -0,0 +1,306 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// TypedAdminClient wraps an admin client with methods that return the parsed
+// results of hadmin.
+type TypedAdminClient struct {
+	IAdminClient
+}
+
+// NewTypedAdminClient generates a typed admin client on top of the client.
+func NewTypedAdminClient(client IAdminClient) TypedAdminClient {
+	return TypedAdminClient{IAdminClient: client}
+}
+
+// StoreNode is a row of `hadmin store status`.
+type StoreNode struct {
+	ID                    int
+	Name                  string
+	State                 string
+	Location              string
+	DataHealth            string
+	StorageState          string
+	ShardOperationalState string
+	HealthStatus          string
+}
+
+// IsAlive reports whether the node is alive and healthy.
+func (n StoreNode) IsAlive() bool {
+	return n.State == "ALIVE" && n.HealthStatus == "HEALTHY"
+}
+
+// ShardState is a row of `hadmin store status --shards`.
+type ShardState struct {
+	NodeID           int
+	Shard            int
+	StorageState     string
+	DataHealth       string
+	OperationalState string
+}
+
+// Maintenance is a row of `hadmin store maintenance list`.
+type Maintenance struct {
+	ID              string
+	Status          string
+	ShardTarget     string
+	SequencerTarget string
+	User            string
+	Reason          string
+}
+
+// ServerNode is a row of `hadmin server status`.
+type ServerNode struct {
+	ID      int
+	State   string
+	Address string
+}
+
+// IsRunning reports whether the node is running.
+func (n ServerNode) IsRunning() bool {
+	return strings.EqualFold(n.State, "Running")
+}
+
+// ClusterStatus gathers the nodes of HStore and HServer.
+type ClusterStatus struct {
+	StoreNodes  []StoreNode
+	ServerNodes []ServerNode
+}
+
+// IsHealthy reports whether every node of the cluster is alive.
+func (s ClusterStatus) IsHealthy() bool {
+	if len(s.StoreNodes) == 0 || len(s.ServerNodes) == 0 {
+		return false
+	}
+	for _, node := range s.StoreNodes {
+		if !node.IsAlive() {
+			return false
+		}
+	}
+	for _, node := range s.ServerNodes {
+		if !node.IsRunning() {
+			return false
+		}
+	}
+	return true
+}
+
+// ServerVersion is the version of HServer.
+type ServerVersion struct {
+	Version string
+	Commit  string
+}
+
+func (c TypedAdminClient) ListStoreNodes(ctx context.Context) ([]StoreNode, error) {
+	output, err := c.CallStore(ctx, "status")
+	if err != nil {
+		return nil, err
+	}
+	return ParseStoreNodes(output)
+}
+
+func (c TypedAdminClient) GetShardStates(ctx context.Context) ([]ShardState, error) {
+	output, err := c.CallStore(ctx, "status", "--shards")
+	if err != nil {
+		return nil, err
+	}
+	return ParseShardStates(output)
+}
+
+func (c TypedAdminClient) ListMaintenances(ctx context.Context) ([]Maintenance, error) {
+	output, err := c.MaintenanceStore(ctx, MaintenanceActionList)
+	if err != nil {
+		return nil, err
+	}
+	return ParseMaintenances(output)
+}
+
+func (c TypedAdminClient) ListServerNodes(ctx context.Context) ([]ServerNode, error) {
+	output, err := c.CallServer(ctx, "status")
+	if err != nil {
+		return nil, err
+	}
+	return ParseServerNodes(output)
+}
+
+func (c TypedAdminClient) GetClusterStatus(ctx context.Context) (status ClusterStatus, err error) {
+	if status.StoreNodes, err = c.ListStoreNodes(ctx); err != nil {
+		return status, fmt.Errorf("failed to list store nodes: %w", err)
+	}
+	if status.ServerNodes, err = c.ListServerNodes(ctx); err != nil {
+		return status, fmt.Errorf("failed to list server nodes: %w", err)
+	}
+	return status, nil
+}
+
+func (c TypedAdminClient) GetServerVersion(ctx context.Context) (ServerVersion, error) {
+	output, err := c.CallServer(ctx, "version")
+	if err != nil {
+		return ServerVersion{}, err
+	}
+	return ParseServerVersion(output)
+}
+
+// ParseStoreNodes parses the output of `hadmin store status`.
+func ParseStoreNodes(output string) ([]StoreNode, error) {
+	table, err := ParseTable(output)
+	if err != nil {
+		return nil, err
+	}
+	if err = table.requireColumns("ID", "NAME", "STATE"); err != nil {
+		return nil, err
+	}
+
+	nodes := make([]StoreNode, 0, len(table.Rows))
+	for _, row := range table.Rows {
+		id, err := table.GetInt(row, "ID")
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, StoreNode{
+			ID:                    id,
+			Name:                  table.Get(row, "NAME"),
+			State:                 table.Get(row, "STATE"),
+			Location:              table.Get(row, "LOCATION"),
+			DataHealth:            table.Get(row, "DATA HEALTH"),
+			StorageState:          table.Get(row, "STORAGE STATE"),
+			ShardOperationalState: table.Get(row, "SHARD OP."),
+			HealthStatus:          table.Get(row, "HEALTH STATUS"),
+		})
+	}
+	return nodes, nil
+}
+
+// ParseShardStates parses the output of `hadmin store status --shards`.
+func ParseShardStates(output string) ([]ShardState, error) {
+	table, err := ParseTable(output)
+	if err != nil {
+		return nil, err
+	}
+	if err = table.requireColumns("NODE ID", "SHARD"); err != nil {
+		return nil, err
+	}
+
+	shards := make([]ShardState, 0, len(table.Rows))
+	for _, row := range table.Rows {
+		nodeID, err := table.GetInt(row, "NODE ID")
+		if err != nil {
+			return nil, err
+		}
+		shard, err := table.GetInt(row, "SHARD")
+		if err != nil {
+			return nil, err
+		}
+		shards = append(shards, ShardState{
+			NodeID:           nodeID,
+			Shard:            shard,
+			StorageState:     table.Get(row, "STORAGE STATE"),
+			DataHealth:       table.Get(row, "DATA HEALTH"),
+			OperationalState: table.Get(row, "OPERATIONAL STATE"),
+		})
+	}
+	return shards, nil
+}
+
+// ParseMaintenances parses the output of `hadmin store maintenance list`.
+func ParseMaintenances(output string) ([]Maintenance, error) {
+	if strings.Contains(output, "No maintenances") {
+		return nil, nil
+	}
+
+	table, err := ParseTable(output)
+	if err != nil {
+		return nil, err
+	}
+	if err = table.requireColumns("MAINTENANCE ID", "STATUS"); err != nil {
+		return nil, err
+	}
+
+	maintenances := make([]Maintenance, 0, len(table.Rows))
+	for _, row := range table.Rows {
+		maintenances = append(maintenances, Maintenance{
+			ID:              table.Get(row, "MAINTENANCE ID"),
+			Status:          table.Get(row, "STATUS"),
+			ShardTarget:     table.Get(row, "SHARD TARGET"),
+			SequencerTarget: table.Get(row, "SEQUENCER TARGET"),
+			User:            table.Get(row, "CREATED BY"),
+			Reason:          table.Get(row, "REASON"),
+		})
+	}
+	return maintenances, nil
+}
+
+// ParseServerNodes parses the output of `hadmin server status`.
+func ParseServerNodes(output string) ([]ServerNode, error) {
+	table, err := ParseTable(output)
+	if err != nil {
+		return nil, err
+	}
+	if err = table.requireColumns("NODE ID", "STATE"); err != nil {
+		return nil, err
+	}
+
+	nodes := make([]ServerNode, 0, len(table.Rows))
+	for _, row := range table.Rows {
+		id, err := table.GetInt(row, "NODE ID")
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, ServerNode{
+			ID:      id,
+			State:   table.Get(row, "STATE"),
+			Address: table.Get(row, "ADDRESS"),
+		})
+	}
+	return nodes, nil
+}
+
+var (
+	versionRegexp = regexp.MustCompile(`v?\d+\.\d+\.\d+[\w.+-]*`)
+	commitRegexp  = regexp.MustCompile(`(?i)commit[:\s]+([0-9a-f]{7,40})`)
+)
+
+// ParseServerVersion parses the output of `hadmin server version`, either
+// plain text or the JSON result of the admin command.
+func ParseServerVersion(output string) (version ServerVersion, err error) {
+	output = strings.TrimSpace(output)
+	if strings.HasPrefix(output, "{") {
+		var result AdminCommandResult
+		if err = json.Unmarshal([]byte(output), &result); err != nil {
+			return version, fmt.Errorf("failed to unmarshal server version: %w", err)
+		}
+		if err = json.Unmarshal(result.Content, &output); err != nil {
+			return version, fmt.Errorf("failed to unmarshal server version: %w", err)
+		}
+	}
+
+	version.Version = versionRegexp.FindString(output)
+	if version.Version == "" {
+		return version, fmt.Errorf("no version found in output: %s", output)
+	}
+	if match := commitRegexp.FindStringSubmatch(output); match != nil {
+		version.Commit = match[1]
+	}
+	return version, nil
+}
@@ -0,0 +1,129 @@
+package admin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func readFixture(name string) string {
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	Expect(err).To(Succeed())
+	return string(data)
+}
+
+var _ = Describe("admin/typed", func() {
+	It("should parse store nodes", func() {
+		nodes, err := ParseStoreNodes(readFixture("store_status.txt"))
+		Expect(err).To(Succeed())
+		Expect(nodes).To(HaveLen(3))
+		Expect(nodes[0]).To(Equal(StoreNode{
+			ID:                    0,
+			Name:                  "hstore-0",
+			State:                 "ALIVE",
+			DataHealth:            "HEALTHY(1)",
+			StorageState:          "READ_WRITE(1)",
+			ShardOperationalState: "ENABLED(1)",
+			HealthStatus:          "HEALTHY",
+		}))
+		Expect(nodes[0].IsAlive()).To(BeTrue())
+		Expect(nodes[2].IsAlive()).To(BeFalse())
+	})
+
+	It("should parse shard states", func() {
+		shards, err := ParseShardStates(readFixture("store_shards.txt"))
+		Expect(err).To(Succeed())
+		Expect(shards).To(HaveLen(3))
+		Expect(shards[2]).To(Equal(ShardState{
+			NodeID:           1,
+			Shard:            0,
+			StorageState:     "READ_ONLY",
+			DataHealth:       "HEALTHY",
+			OperationalState: "MAY_DISAPPEAR",
+		}))
+	})
+
+	It("should parse maintenances", func() {
+		maintenances, err := ParseMaintenances(readFixture("maintenance_list.txt"))
+		Expect(err).To(Succeed())
+		Expect(maintenances).To(HaveLen(2))
+		Expect(maintenances[0]).To(Equal(Maintenance{
+			ID:              "4f0f2d8e-6f8b-4f6c-9a1d-0d0d7b1b5e11",
+			Status:          "IN_PROGRESS",
+			ShardTarget:     "DRAINED",
+			SequencerTarget: "DISABLED",
+			User:            "operator",
+			Reason:          "scale in hstore-2",
+		}))
+
+		maintenances, err = ParseMaintenances(readFixture("maintenance_list_empty.txt"))
+		Expect(err).To(Succeed())
+		Expect(maintenances).To(BeEmpty())
+	})
+
+	It("should parse server nodes from table and JSON", func() {
+		nodes, err := ParseServerNodes(readFixture("server_status.txt"))
+		Expect(err).To(Succeed())
+		Expect(nodes).To(Equal([]ServerNode{
+			{ID: 100, State: "Running", Address: "hserver-0.hstreamdb-sample-hserver:6570"},
+			{ID: 101, State: "Running", Address: "hserver-1.hstreamdb-sample-hserver:6570"},
+		}))
+
+		nodes, err = ParseServerNodes(readFixture("server_status.json"))
+		Expect(err).To(Succeed())
+		Expect(nodes).To(HaveLen(3))
+		Expect(nodes[2].IsRunning()).To(BeFalse())
+	})
+
+	It("should parse server version", func() {
+		version, err := ParseServerVersion(readFixture("server_version.txt"))
+		Expect(err).To(Succeed())
+		Expect(version).To(Equal(ServerVersion{Version: "v0.19.3", Commit: "6cbb2ca7e1b1c6b2e0bd0e0a4a58b9c3dd8f1e2a"}))
+
+		version, err = ParseServerVersion(readFixture("server_version.json"))
+		Expect(err).To(Succeed())
+		Expect(version).To(Equal(ServerVersion{Version: "v0.19.3", Commit: "6cbb2ca"}))
+	})
+
+	It("should report unexpected output", func() {
+		_, err := ParseStoreNodes("Connection refused")
+		Expect(err).To(HaveOccurred())
+
+		_, err = ParseStoreNodes(readFixture("server_status.txt"))
+		Expect(err).To(MatchError(ContainSubstring("missing columns")))
+
+		_, err = ParseServerNodes(`{"type":"error","content":"permission denied"}`)
+		Expect(err).To(MatchError(ContainSubstring("permission denied")))
+	})
+
+	It("should get cluster status", func() {
+		client := NewTypedAdminClient(&fixtureAdminClient{
+			store:  readFixture("store_status.txt"),
+			server: readFixture("server_status.txt"),
+		})
+
+		status, err := client.GetClusterStatus(context.TODO())
+		Expect(err).To(Succeed())
+		Expect(status.StoreNodes).To(HaveLen(3))
+		Expect(status.ServerNodes).To(HaveLen(2))
+		Expect(status.IsHealthy()).To(BeFalse())
+	})
+})
+
+// fixtureAdminClient returns the same output for every command of a kind.
+type fixtureAdminClient struct {
+	recordingAdminClient
+	store  string
+	server string
+}
+
+func (c *fixtureAdminClient) CallServer(context.Context, ...string) (string, error) {
+	return c.server, nil
+}
+
+func (c *fixtureAdminClient) CallStore(context.Context, ...string) (string, error) {
+	return c.store, nil
+}
@@ -17,47 +17,55 @@ limitations under the License.
 package admin
 
 import (
-	"github.com/go-logr/logr"
+	"context"
+	"fmt"
+
 	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
-	"k8s.io/client-go/rest"
 )
 
 type MaintenanceAction string
 
 const (
 	MaintenanceActionApply MaintenanceAction = "apply"
+	MaintenanceActionList  MaintenanceAction = "list"
 )
 
 type IAdminClient interface {
-	CallServer(args ...string) (string, error)
-	CallStore(args ...string) (string, error)
-	MaintenanceStore(action MaintenanceAction, args ...string) (string, error)
-	GetHMetaStatus() (HMetaStatus, error)
+	CallServer(ctx context.Context, args ...string) (string, error)
+	CallStore(ctx context.Context, args ...string) (string, error)
+	MaintenanceStore(ctx context.Context, action MaintenanceAction, args ...string) (string, error)
+	GetHMetaStatus(ctx context.Context) (HMetaStatus, error)
 }
 
-// AdminClientProvider provides an abstraction for creating clients that
-// communicate with the HStreamDB cluster.
-type AdminClientProvider interface {
-	GetAdminClient(hdb *hapi.HStreamDB) IAdminClient
-}
+// AdminClientMode selects how the admin commands are sent to the cluster.
+type AdminClientMode string
 
-type adminClientProvider struct {
-	// restConfig defines k8s client config.
-	restConfig *rest.Config
+const (
+	// AdminClientModeExec runs hadmin in an admin server pod.
+	AdminClientModeExec AdminClientMode = "exec"
+	// AdminClientModeGRPC sends the server commands to HServer over gRPC, the
+	// other commands are still sent by exec.
+	AdminClientModeGRPC AdminClientMode = "grpc"
+)
 
-	// log defines the logger for the admin client.
-	log logr.Logger
+// ParseAdminClientMode parses the mode from a command line flag.
+func ParseAdminClientMode(mode string) (AdminClientMode, error) {
+	switch m := AdminClientMode(mode); m {
+	case AdminClientModeExec, AdminClientModeGRPC:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown admin client mode %q, expected %s or %s",
+			mode, AdminClientModeExec, AdminClientModeGRPC)
+	}
 }
 
-func (p *adminClientProvider) GetAdminClient(hdb *hapi.HStreamDB) IAdminClient {
-	return NewAdminClient(hdb, p.restConfig, p.log)
-}
+// AdminClientProvider provides an abstraction for creating clients that
+// communicate with the HStreamDB cluster.
+type AdminClientProvider interface {
+	GetAdminClient(hdb *hapi.HStreamDB) IAdminClient
 
-func NewAdminClientProvider(restConfig *rest.Config, log logr.Logger) AdminClientProvider {
-	return &adminClientProvider{
-		restConfig: restConfig,
-		log:        log.WithName("Admin Client"),
-	}
+	// Invalidate drops the cached client of the HStreamDB, e.g. once it is deleted.
+	Invalidate(namespace, name string)
 }
 
 type HMetaStatus struct {
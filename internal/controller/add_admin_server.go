@@ -44,7 +44,7 @@ func (a addAdminServer) reconcile(ctx context.Context, r *HStreamDBReconciler, h
 
 		logger.Info("Create admin server")
 		if err = r.Client.Create(ctx, &deploy); err != nil {
-			return &requeue{curError: err}
+			return &requeue{curError: err, reason: reasonApplyFailed}
 		}
 		return nil
 	}
@@ -59,7 +59,7 @@ func (a addAdminServer) reconcile(ctx context.Context, r *HStreamDBReconciler, h
 	existingDeploy.Labels = deploy.Labels
 	existingDeploy.Spec = deploy.Spec
 	if err = r.Update(ctx, existingDeploy); err != nil {
-		return &requeue{curError: err}
+		return &requeue{curError: err, reason: reasonApplyFailed}
 	}
 	return nil
 }
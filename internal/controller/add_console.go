@@ -65,7 +65,7 @@ func (a addConsole) reconcile(ctx context.Context, r *HStreamDBReconciler, hdb *
 
 		logger.Info("Create console")
 		if err = r.Client.Create(ctx, &deploy); err != nil {
-			return &requeue{curError: err}
+			return &requeue{curError: err, reason: reasonApplyFailed}
 		}
 		return nil
 	}
@@ -80,7 +80,7 @@ func (a addConsole) reconcile(ctx context.Context, r *HStreamDBReconciler, hdb *
 	existingDeploy.Labels = deploy.Labels
 	existingDeploy.Spec = deploy.Spec
 	if err = r.Update(ctx, existingDeploy); err != nil {
-		return &requeue{curError: err}
+		return &requeue{curError: err, reason: reasonApplyFailed}
 	}
 	return nil
 }
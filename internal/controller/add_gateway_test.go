@@ -50,7 +50,7 @@ var _ = Describe("AddGateway", func() {
 		}).Should(BeTrue())
 	})
 
-	When("gateway pointer is set, but HServer not ready", func() {
+	When("gateway pointer is set, but HServer not bootstrapped", func() {
 		JustBeforeEach(func() {
 			gateway := &hapi.Gateway{}
 			gateway.Endpoint = "localhost"
@@ -63,7 +63,7 @@ var _ = Describe("AddGateway", func() {
 			Expect(k8sClient.Update(ctx, hdb.DeepCopy())).Should(Succeed())
 		})
 
-		It("should not create gateway if HServer not ready", func() {
+		It("should not create gateway if HServer not bootstrapped", func() {
 			By("reconcile")
 			requeue = addGateway.reconcile(ctx, clusterReconciler, hdb)
 			Expect(requeue.curError).To(BeNil())
@@ -80,7 +80,7 @@ var _ = Describe("AddGateway", func() {
 		})
 	})
 
-	When("gateway pointer is set, and HServer is ready, not enable mTLS", func() {
+	When("gateway pointer is set, and HServer is bootstrapped, not enable mTLS", func() {
 		JustBeforeEach(func() {
 			gateway := &hapi.Gateway{}
 			gateway.Endpoint = "localhost"
@@ -93,8 +93,9 @@ var _ = Describe("AddGateway", func() {
 			Expect(k8sClient.Update(ctx, hdb.DeepCopy())).Should(Succeed())
 
 			hdb.SetCondition(metav1.Condition{
-				Type:   hapi.HServerReady,
+				Type:   hapi.HServerBootstrapped,
 				Status: metav1.ConditionTrue,
+				Reason: hapi.ReasonBootstrapped,
 			})
 			Expect(k8sClient.Status().Patch(ctx, hdb.DeepCopy(), client.MergeFrom(hdb))).Should(Succeed())
 		})
@@ -130,7 +131,7 @@ var _ = Describe("AddGateway", func() {
 		})
 	})
 
-	When("gateway pointer is set, and HServer is ready, enable mTLS", func() {
+	When("gateway pointer is set, and HServer is bootstrapped, enable mTLS", func() {
 		JustBeforeEach(func() {
 			gateway := &hapi.Gateway{}
 			gateway.Endpoint = "localhost"
@@ -146,8 +147,9 @@ var _ = Describe("AddGateway", func() {
 			Expect(k8sClient.Update(ctx, hdb.DeepCopy())).Should(Succeed())
 
 			hdb.SetCondition(metav1.Condition{
-				Type:   hapi.HServerReady,
+				Type:   hapi.HServerBootstrapped,
 				Status: metav1.ConditionTrue,
+				Reason: hapi.ReasonBootstrapped,
 			})
 			Expect(k8sClient.Status().Patch(ctx, hdb.DeepCopy(), client.MergeFrom(hdb))).Should(Succeed())
 		})
@@ -23,8 +23,8 @@ func (a addGateway) reconcile(ctx context.Context, r *HStreamDBReconciler, hdb *
 	}
 
 	logger := log.WithValues("namespace", hdb.Namespace, "instance", hdb.Name, "reconciler", "add gateway")
-	if !hdb.IsConditionTrue(hapi.HServerReady) {
-		return &requeue{message: "wait for HServer nodes to be ready", delay: 10 * time.Second}
+	if !isBootstrapped(hdb, hapi.HServerBootstrapped, hapi.HServerReady) {
+		return &requeue{message: "wait for HServer to be bootstrapped", delay: 10 * time.Second, reason: reasonWaitingForHServer}
 	}
 
 	deploy := a.getDeployment(ctx, r, hdb)
@@ -41,7 +41,7 @@ func (a addGateway) reconcile(ctx context.Context, r *HStreamDBReconciler, hdb *
 
 		logger.Info("Create gateway")
 		if err = r.Client.Create(ctx, &deploy); err != nil {
-			return &requeue{curError: err}
+			return &requeue{curError: err, reason: reasonApplyFailed}
 		}
 		return nil
 	}
@@ -56,7 +56,7 @@ func (a addGateway) reconcile(ctx context.Context, r *HStreamDBReconciler, hdb *
 	existingDeploy.Labels = deploy.Labels
 	existingDeploy.Spec = deploy.Spec
 	if err = r.Update(ctx, existingDeploy); err != nil {
-		return &requeue{curError: err}
+		return &requeue{curError: err, reason: reasonApplyFailed}
 	}
 	return nil
 }
@@ -115,7 +115,7 @@ func (a addGateway) getContainer(ctx context.Context, r *HStreamDBReconciler, hd
 
 	container.Ports = extendPorts(container.Ports, corev1.ContainerPort{Name: "port", ContainerPort: gateway.Port})
 
-	port := findHServerPort(ctx, r, hdb)
+	port := findHServerPort(ctx, r.Client, hdb)
 	hServerSvc := internal.GetHeadlessService(hdb, hapi.ComponentTypeHServer)
 	address := fmt.Sprintf("hstream://%s:%d", hServerSvc.Name+"."+hdb.Namespace, port)
 
@@ -144,7 +144,7 @@ func (a addGateway) getContainer(ctx context.Context, r *HStreamDBReconciler, hd
 	return append([]corev1.Container{container}, gateway.SidecarContainers...)
 }
 
-func findHServerPort(ctx context.Context, r *HStreamDBReconciler, hdb *hapi.HStreamDB) int32 {
+func findHServerPort(ctx context.Context, c client.Client, hdb *hapi.HStreamDB) int32 {
 	hServerContainerName := hdb.Spec.HServer.Container.Name
 	if hServerContainerName == "" {
 		hServerContainerName = string(hapi.ComponentTypeHServer)
@@ -153,7 +153,7 @@ func findHServerPort(ctx context.Context, r *HStreamDBReconciler, hdb *hapi.HStr
 	hServer := &appsv1.StatefulSet{
 		ObjectMeta: internal.GetObjectMetadata(hdb, nil, hapi.ComponentTypeHServer),
 	}
-	_ = r.Get(ctx, client.ObjectKeyFromObject(hServer), hServer)
+	_ = c.Get(ctx, client.ObjectKeyFromObject(hServer), hServer)
 
 	for _, container := range hServer.Spec.Template.Spec.Containers {
 		if container.Name == hServerContainerName {
@@ -40,7 +40,7 @@ func (a addHMeta) reconcile(ctx context.Context, r *HStreamDBReconciler, hdb *ha
 
 		logger.Info("Create HMeta")
 		if err = r.Client.Create(ctx, &sts); err != nil {
-			return &requeue{curError: err}
+			return &requeue{curError: err, reason: reasonApplyFailed}
 		}
 		return nil
 	}
@@ -58,7 +58,7 @@ func (a addHMeta) reconcile(ctx context.Context, r *HStreamDBReconciler, hdb *ha
 	existingSts.Spec.UpdateStrategy = sts.Spec.UpdateStrategy
 	existingSts.Spec.MinReadySeconds = sts.Spec.MinReadySeconds
 	if err = r.Update(ctx, existingSts); err != nil {
-		return &requeue{curError: err}
+		return &requeue{curError: err, reason: reasonApplyFailed}
 	}
 	return nil
 }
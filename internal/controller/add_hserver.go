@@ -37,7 +37,7 @@ func (a addHServer) reconcile(ctx context.Context, r *HStreamDBReconciler, hdb *
 
 		logger.Info("Create hServer")
 		if err = r.Client.Create(ctx, &sts); err != nil {
-			return &requeue{curError: err}
+			return &requeue{curError: err, reason: reasonApplyFailed}
 		}
 		return nil
 	}
@@ -52,7 +52,7 @@ func (a addHServer) reconcile(ctx context.Context, r *HStreamDBReconciler, hdb *
 	existingSts.Labels = sts.Labels
 	existingSts.Spec = sts.Spec
 	if err = r.Update(ctx, existingSts); err != nil {
-		return &requeue{curError: err}
+		return &requeue{curError: err, reason: reasonApplyFailed}
 	}
 	return nil
 }
@@ -39,7 +39,7 @@ func (a addHStore) reconcile(ctx context.Context, r *HStreamDBReconciler, hdb *h
 
 		logger.Info("Create HStore")
 		if err = r.Client.Create(ctx, &sts); err != nil {
-			return &requeue{curError: err}
+			return &requeue{curError: err, reason: reasonApplyFailed}
 		}
 		return nil
 	}
@@ -57,7 +57,7 @@ func (a addHStore) reconcile(ctx context.Context, r *HStreamDBReconciler, hdb *h
 	existingSts.Spec.UpdateStrategy = sts.Spec.UpdateStrategy
 	existingSts.Spec.MinReadySeconds = sts.Spec.MinReadySeconds
 	if err = r.Update(ctx, existingSts); err != nil {
-		return &requeue{curError: err}
+		return &requeue{curError: err, reason: reasonApplyFailed}
 	}
 	return nil
 }
@@ -16,7 +16,7 @@ type bootstrapHServer struct{}
 func (a bootstrapHServer) reconcile(ctx context.Context, r *HStreamDBReconciler, hdb *hapi.HStreamDB) *requeue {
 	logger := log.WithValues("namespace", hdb.Namespace, "instance", hdb.Name, "reconciler", "bootstrap hServer")
 
-	if hdb.IsConditionTrue(hapi.HServerReady) {
+	if isBootstrapped(hdb, hapi.HServerBootstrapped, hapi.HServerReady) {
 		return nil
 	}
 
@@ -32,26 +32,26 @@ func (a bootstrapHServer) reconcile(ctx context.Context, r *HStreamDBReconciler,
 	}
 	if err := checkPodRunningStatus(ctx, r.Client, hdb, sts); err != nil {
 		// we only set the message to log, and reconcile after several second
-		return &requeue{message: err.Error(), delay: time.Second}
+		return &requeue{message: err.Error(), delay: time.Second, reason: reasonWaitingForPods}
 	}
 
 	logger.Info("Bootstrap hServer")
-	if _, err := r.AdminClientProvider.GetAdminClient(hdb).CallServer(
+	if _, err := r.AdminClientProvider.GetAdminClient(hdb).CallServer(ctx,
 		"init",
 		"--host", internal.GetHeadlessService(hdb, hapi.ComponentTypeHServer).Name,
 	); err != nil {
-		return &requeue{message: err.Error(), delay: time.Second * 5}
+		return &requeue{message: err.Error(), delay: time.Second * 5, reason: adminFailureReason(err, reasonBootstrapFailed)}
 	}
 
 	hdb.SetCondition(metav1.Condition{
-		Type:    hapi.HServerReady,
+		Type:    hapi.HServerBootstrapped,
 		Status:  metav1.ConditionTrue,
-		Reason:  hapi.HServerReady,
+		Reason:  hapi.ReasonBootstrapped,
 		Message: "HServer has been bootstrapped",
 	})
 	logger.Info("Update HServer status")
 	if err := r.Status().Update(ctx, hdb); err != nil {
-		return &requeue{curError: fmt.Errorf("update HStore status failed: %w", err)}
+		return &requeue{curError: fmt.Errorf("update HServer status failed: %w", err), reason: reasonStatusUpdateFailed}
 	}
 	return nil
 }
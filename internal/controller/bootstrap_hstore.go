@@ -19,7 +19,7 @@ type bootstrapHStore struct{}
 func (a bootstrapHStore) reconcile(ctx context.Context, r *HStreamDBReconciler, hdb *hapi.HStreamDB) *requeue {
 	logger := log.WithValues("namespace", hdb.Namespace, "instance", hdb.Name, "reconciler", "bootstrap HStore")
 
-	if hdb.IsConditionTrue(hapi.HStoreReady) {
+	if isBootstrapped(hdb, hapi.HStoreBootstrapped, hapi.HStoreReady) {
 		return nil
 	}
 
@@ -33,7 +33,7 @@ func (a bootstrapHStore) reconcile(ctx context.Context, r *HStreamDBReconciler,
 	}
 	if err = checkPodRunningStatus(ctx, r.Client, hdb, sts); err != nil {
 		// print message only to log, wait for reconciling after several second
-		return &requeue{message: err.Error(), delay: time.Second}
+		return &requeue{message: err.Error(), delay: time.Second, reason: reasonWaitingForPods}
 	}
 
 	logger.Info("Bootstrap HStore")
@@ -45,27 +45,27 @@ func (a bootstrapHStore) reconcile(ctx context.Context, r *HStreamDBReconciler,
 		metadataReplication = int(*hdb.Spec.Config.MetadataReplicateAcross)
 	}
 
-	if _, err = r.AdminClientProvider.GetAdminClient(hdb).CallStore(
+	if _, err = r.AdminClientProvider.GetAdminClient(hdb).CallStore(ctx,
 		"nodes-config", "bootstrap",
 		"--metadata-replicate-across", fmt.Sprintf("node:%d", metadataReplication),
 	); err != nil {
-		return &requeue{message: err.Error(), delay: time.Second * 5}
+		return &requeue{message: err.Error(), delay: time.Second * 5, reason: adminFailureReason(err, reasonBootstrapFailed)}
 	}
 
 	hdb.SetCondition(metav1.Condition{
-		Type:    hapi.HStoreReady,
+		Type:    hapi.HStoreBootstrapped,
 		Status:  metav1.ConditionTrue,
-		Reason:  hapi.HStoreReady,
+		Reason:  hapi.ReasonBootstrapped,
 		Message: "HStore has been bootstrapped",
 	})
 	logger.Info("Update HStore status")
 	if err = r.Status().Update(ctx, hdb); err != nil {
-		return &requeue{curError: fmt.Errorf("update HStore status failed: %w", err)}
+		return &requeue{curError: fmt.Errorf("update HStore status failed: %w", err), reason: reasonStatusUpdateFailed}
 	}
 
 	// we still need to delay several second before deploying hServer
 	// while the first time we bootstrap successfully
-	return &requeue{delay: time.Second}
+	return &requeue{delay: time.Second, reason: reasonBootstrapSucceeded}
 }
 
 func checkPodRunningStatus(ctx context.Context, client client.Client, hdb *hapi.HStreamDB, obj client.Object) error {
@@ -19,6 +19,8 @@ package controller
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/go-logr/logr"
@@ -29,26 +31,41 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kube-openapi/pkg/validation/spec"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/hstreamdb/hstream-operator/api/v1beta1"
+	"github.com/hstreamdb/hstream-operator/internal"
 	"github.com/hstreamdb/hstream-operator/pkg/connectorgen"
 )
 
 // ConnectorReconciler reconciles a Connector object
 type ConnectorReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=apps.hstream.io,resources=connectors,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps.hstream.io,resources=connectors/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=apps.hstream.io,resources=connectors/finalizers,verbs=update
-//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.hstream.io,resources=connectorclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=apps.hstream.io,resources=hstreamdbs,verbs=get;list;watch
+//+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=deployments,verbs=create;update;patch;delete
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -71,109 +88,277 @@ func (r *ConnectorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, nil
 	}
 
-	configMapNames := []string{}
-	for _, stream := range connector.Spec.Streams {
-		configMapNames = append(configMapNames, v1beta1.GenConnectorConfigMapNameForStream(connector.Name, stream))
-	}
-	var configs []map[string]interface{}
-	cfgs, err := r.mergePatchesIntoConfigs(ctx, log, connector)
+	configs, err := r.mergePatchesIntoConfigs(ctx, log, connector)
 	if err != nil {
 		log.Error(err, "fail to merge connector config patches into config template")
 
+		return ctrl.Result{}, utilerrors.NewAggregate([]error{err,
+			r.updateConnectorStatus(ctx, &connector, newConnectorStatus(&connector, nil, err)),
+		})
+	}
+
+	class, schema, err := r.resolveConnectorClass(ctx, &connector)
+	if err != nil {
+		if errors.Is(err, connectorgen.ErrUnknownConnectorType) || errors.Is(err, connectorgen.ErrInvalidConfigSchema) {
+			// Requeued once the ConnectorClass is created or fixed.
+			return ctrl.Result{}, r.updateConnectorStatus(ctx, &connector, newConnectorStatus(&connector, nil, err))
+		}
 		return ctrl.Result{}, err
 	}
-	configs = cfgs
 
-	for index, name := range configMapNames {
-		var connectorConfigMap corev1.ConfigMap
-		if err := r.Get(ctx, types.NamespacedName{
-			Namespace: connector.Namespace,
-			Name:      name,
-		}, &connectorConfigMap); err != nil {
-			if !k8sErrors.IsNotFound(err) {
-				return ctrl.Result{}, err
-			}
+	serviceURL, pending, err := r.resolveHServerURL(ctx, &connector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if pending != nil {
+		// Requeued once the HStreamDB becomes ready.
+		return ctrl.Result{}, r.updateConnectorStatus(ctx, &connector, newPendingConnectorStatus(&connector, pending))
+	}
 
-			configWithService := map[string]interface{}{
-				"connector": configs[index],
-				"hstream": map[string]string{
-					"serviceUrl": "hstream://" + connector.Spec.HServerEndpoint,
-				},
+	var errs []error
+	streams := make([]connectorStream, 0, len(connector.Spec.Streams))
+	for index, stream := range connector.Spec.Streams {
+		observed, err := r.reconcileConnectorStream(ctx, &connector, class, schema, stream, serviceURL, configs[index])
+		if err != nil {
+			observed.status.Error = err.Error()
+			// An invalid config is not retried until the connector, its
+			// template or the Secrets it refers to change.
+			if !observed.invalidConfig {
+				errs = append(errs, err)
 			}
+		}
+		streams = append(streams, observed)
+	}
 
-			configJson, _ := json.Marshal(configWithService)
+	if err = r.removeStaleConnectorResources(ctx, &connector); err != nil {
+		log.Error(err, "fail to remove the resources of removed streams", "Connector", connector.Name)
 
-			connectorConfigMap := corev1.ConfigMap{
-				ObjectMeta: metav1.ObjectMeta{
-					Namespace: connector.Namespace,
-					Name:      name,
-				},
-				Data: map[string]string{
-					"config.json": string(configJson),
-				},
-			}
+		errs = append(errs, err)
+	}
 
-			if err := controllerutil.SetControllerReference(&connector, &connectorConfigMap, r.Scheme); err != nil {
-				return ctrl.Result{}, err
-			}
+	errs = append(errs, r.updateConnectorStatus(ctx, &connector, newConnectorStatus(&connector, streams, nil)))
+	return ctrl.Result{}, utilerrors.NewAggregate(errs)
+}
+
+// reconcileConnectorStream applies the config Secret and Deployment serving a
+// stream, and returns the stream observed.
+func (r *ConnectorReconciler) reconcileConnectorStream(ctx context.Context, connector *v1beta1.Connector,
+	class *v1beta1.ConnectorClass, schema *spec.Schema, stream, serviceURL string, config map[string]interface{}) (connectorStream, error) {
+	observed := connectorStream{
+		status: v1beta1.ConnectorStreamStatus{
+			Stream:     stream,
+			Deployment: v1beta1.GenConnectorDeploymentName(connector.Name, stream),
+		},
+	}
 
-			err = r.Create(ctx, &connectorConfigMap)
-			if err != nil {
-				log.Error(err, "fail to create ConfigMap for Connector",
-					"Connector", connector.Name,
-					"ConfigMap", name,
-				)
+	resolved, err := resolveSecretRefs(ctx, r.Client, connector.Namespace, config)
+	if err != nil {
+		return observed, fmt.Errorf("fail to resolve secret references: %w", err)
+	}
+	// The pods keep running with the config last applied until it is fixed.
+	if err = connectorgen.ValidateConfig(schema, resolved.(map[string]interface{})); err != nil {
+		observed.invalidConfig = true
+		return observed, err
+	}
 
-				return ctrl.Result{}, err
-			}
+	secret, err := genConnectorConfigSecret(connector, stream, serviceURL, resolved.(map[string]interface{}))
+	if err != nil {
+		return observed, err
+	}
+	if err = r.applyConnectorConfigSecret(ctx, connector, &secret); err != nil {
+		log.Error(err, "fail to apply config Secret for Connector",
+			"Connector", connector.Name,
+			"Secret", secret.Name,
+		)
 
-			err = r.createConnectorDeployment(ctx, &connector, connector.Spec.Streams[index], name)
-			if err != nil {
-				log.Error(err, "fail to create Deployment for Connector",
-					"Connector", connector.Name,
-					"Deployment", v1beta1.GenConnectorDeploymentName(connector.Name, connector.Spec.Streams[index]),
-				)
+		return observed, err
+	}
 
-				return ctrl.Result{}, err
-			}
+	if connector.Spec.Storage != nil {
+		pvc := genConnectorDataPVC(connector, stream)
+		if observed.ignoredStorageFields, err = r.applyConnectorDataPVC(ctx, connector, &pvc); err != nil {
+			log.Error(err, "fail to apply data PVC for Connector",
+				"Connector", connector.Name,
+				"PersistentVolumeClaim", pvc.Name,
+			)
+
+			return observed, err
 		}
 	}
 
-	// TODO: handle update logic.
+	deployment := genConnectorDeployment(connector, class, stream, &secret)
+	if err = r.applyConnectorDeployment(ctx, connector, &deployment); err != nil {
+		log.Error(err, "fail to apply Deployment for Connector",
+			"Connector", connector.Name,
+			"Deployment", deployment.Name,
+		)
 
-	return ctrl.Result{}, nil
+		return observed, err
+	}
+
+	observed.deployment = &deployment
+	observed.status.ReadyReplicas = deployment.Status.ReadyReplicas
+	observed.status.ConfigHash = secret.Annotations[hapi.LastSpecKey]
+	return observed, nil
 }
 
+// resolveConnectorClass returns the class of the connector type and the
+// schema of its config, which is nil if the class does not declare one.
+func (r *ConnectorReconciler) resolveConnectorClass(ctx context.Context, connector *v1beta1.Connector) (*v1beta1.ConnectorClass, *spec.Schema, error) {
+	class, err := connectorgen.ResolveConnectorClass(ctx, r.Client, connector.Spec.Type)
+	if err != nil {
+		return nil, nil, err
+	}
+	schema, err := connectorgen.ConfigSchema(class)
+	if err != nil {
+		return nil, nil, err
+	}
+	return class, schema, nil
+}
+
+func (r *ConnectorReconciler) updateConnectorStatus(ctx context.Context, connector *v1beta1.Connector, status v1beta1.ConnectorStatus) error {
+	connector.Status = status
+	return r.Status().Update(ctx, connector)
+}
+
+const (
+	// connectorTemplateNameField indexes the Connectors by the name of their template.
+	connectorTemplateNameField = ".spec.templateName"
+	// connectorTypeField indexes the Connectors by their type.
+	connectorTypeField = ".spec.type"
+)
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ConnectorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1beta1.Connector{}, connectorTemplateNameField,
+		func(obj client.Object) []string {
+			return []string{obj.(*v1beta1.Connector).Spec.TemplateName}
+		}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1beta1.Connector{}, connectorSecretsField,
+		func(obj client.Object) []string {
+			return connectorSecretNames(obj.(*v1beta1.Connector))
+		}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1beta1.ConnectorTemplate{}, templateSecretsField,
+		func(obj client.Object) []string {
+			return templateSecretNames(obj.(*v1beta1.ConnectorTemplate))
+		}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1beta1.Connector{}, connectorTypeField,
+		func(obj client.Object) []string {
+			return []string{string(obj.(*v1beta1.Connector).Spec.Type)}
+		}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1beta1.Connector{}, hstreamDBRefField,
+		func(obj client.Object) []string {
+			connector := obj.(*v1beta1.Connector)
+			if key := connectorHStreamDBKey(connector); key != nil {
+				return []string{hstreamDBRefKey(key.Namespace, connector.Spec.HStreamDBRef.HStreamDBReference)}
+			}
+			return nil
+		}); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1beta1.Connector{}).
-		Owns(&corev1.ConfigMap{}).
+		// Only the metadata of Secrets is cached, since the cache would hold
+		// every Secret of the cluster. The client reads them from the API
+		// server, see ClientDisableCacheFor of the manager.
+		Owns(&corev1.Secret{}, builder.OnlyMetadata).
+		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.PersistentVolumeClaim{}).
+		// Roll the connector pods once a Secret referred to by the config changes.
+		Watches(&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForSecret),
+			builder.OnlyMetadata).
+		// Re-render the configs of the connectors once their template changes.
+		Watches(&source.Kind{Type: &v1beta1.ConnectorTemplate{}},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForConnectorTemplate),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Roll the connector pods once the class of their type changes.
+		Watches(&source.Kind{Type: &v1beta1.ConnectorClass{}},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForConnectorClass),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Create the connector pods once their HStreamDB becomes ready, and
+		// resolve HServer again once its spec changes.
+		Watches(&source.Kind{Type: &hapi.HStreamDB{}},
+			handler.EnqueueRequestsFromMapFunc(requestsForHStreamDB(mgr.GetClient(), &v1beta1.ConnectorList{})),
+			builder.WithPredicates(predicate.Or(hstreamDBReadinessChanged, predicate.GenerationChangedPredicate{}))).
 		Complete(r)
 }
 
-func (r *ConnectorReconciler) mergePatchesIntoConfigs(ctx context.Context, logger logr.Logger, connector v1beta1.Connector) ([]map[string]interface{}, error) {
-	templateConfigMapName := v1beta1.GenConnectorConfigMapName(connector.Spec.TemplateName, true)
-	templateConfigMapNamespacedName := types.NamespacedName{
-		Namespace: connector.Namespace,
-		Name:      templateConfigMapName,
+// requestsForConnectorTemplate maps a ConnectorTemplate to the Connectors using it.
+func (r *ConnectorReconciler) requestsForConnectorTemplate(obj client.Object) []ctrl.Request {
+	var connectors v1beta1.ConnectorList
+	if err := r.List(context.Background(), &connectors,
+		client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{connectorTemplateNameField: obj.GetName()}); err != nil {
+		log.Error(err, "fail to list the Connectors of ConnectorTemplate", "ConnectorTemplate", obj.GetName())
+		return nil
 	}
 
-	var templateConfigMap corev1.ConfigMap
-	if err := r.Get(ctx, templateConfigMapNamespacedName, &templateConfigMap); err != nil {
-		logger.Error(err, "fail to fetch ConfigMap which stores ConnectorTemplate config")
+	requests := make([]ctrl.Request, 0, len(connectors.Items))
+	for i := range connectors.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&connectors.Items[i])})
+	}
+	return requests
+}
+
+// requestsForConnectorClass maps a ConnectorClass to the Connectors of its type
+// from any namespace.
+func (r *ConnectorReconciler) requestsForConnectorClass(obj client.Object) []ctrl.Request {
+	var connectors v1beta1.ConnectorList
+	if err := r.List(context.Background(), &connectors,
+		client.MatchingFields{connectorTypeField: obj.GetName()}); err != nil {
+		log.Error(err, "fail to list the Connectors of ConnectorClass", "ConnectorClass", obj.GetName())
+		return nil
+	}
+
+	requests := make([]ctrl.Request, 0, len(connectors.Items))
+	for i := range connectors.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&connectors.Items[i])})
+	}
+	return requests
+}
+
+func (r *ConnectorReconciler) mergePatchesIntoConfigs(ctx context.Context, logger logr.Logger, connector v1beta1.Connector) ([]map[string]interface{}, error) {
+	// The config is read from the template itself rather than its ConfigMap,
+	// which may not be updated yet when the connector is enqueued by a change
+	// of the template.
+	var template v1beta1.ConnectorTemplate
+	if err := r.Get(ctx, types.NamespacedName{
+		Namespace: connector.Namespace,
+		Name:      connector.Spec.TemplateName,
+	}, &template); err != nil {
+		logger.Error(err, "fail to fetch ConnectorTemplate")
 
 		return nil, err
 	}
 
 	var templateConfig map[string]interface{}
-	err := json.Unmarshal([]byte(templateConfigMap.Data["config"]), &templateConfig)
+	err := json.Unmarshal([]byte(template.Spec.Config), &templateConfig)
 	if err != nil {
 		logger.Error(err, "fail to unmarshal ConnectorTemplate config")
 
 		return nil, err
 	}
 
+	var patches map[string]json.RawMessage
+	if connector.Spec.Patches != nil {
+		err = json.Unmarshal(connector.Spec.Patches, &patches)
+		if err != nil {
+			logger.Error(err, "fail to unmarshal Connector patches")
+
+			return nil, err
+		}
+	}
+
 	var configs []map[string]interface{}
 
 	for _, stream := range connector.Spec.Streams {
@@ -182,24 +367,23 @@ func (r *ConnectorReconciler) mergePatchesIntoConfigs(ctx context.Context, logge
 			config[k] = v
 		}
 
+		// A sink connector reads from the stream, while a source connector
+		// writes into it, both take it from the same key.
 		config["stream"] = stream
 
-		var patches map[string]map[string]interface{}
-		if connector.Spec.Patches != nil {
-			err = json.Unmarshal(connector.Spec.Patches, &patches)
-			if err != nil {
-				logger.Error(err, "fail to unmarshal Connector patches")
+		// The global patch is applied first, so that the patch of a stream can
+		// override it.
+		config, err = connectorgen.ApplyPatch(config, connector.Spec.PatchType, connector.Spec.GlobalPatch)
+		if err != nil {
+			logger.Error(err, "fail to apply Connector global patch", "stream", stream)
 
-				return nil, err
-			}
+			return nil, fmt.Errorf("invalid global patch: %w", err)
 		}
+		config, err = connectorgen.ApplyPatch(config, connector.Spec.PatchType, patches[stream])
+		if err != nil {
+			logger.Error(err, "fail to apply Connector patch", "stream", stream)
 
-		if connector.Spec.Patches != nil {
-			if val, ok := patches[stream]; ok {
-				for k, v := range val {
-					config[k] = v
-				}
-			}
+			return nil, fmt.Errorf("invalid patch of stream %s: %w", stream, err)
 		}
 
 		configs = append(configs, config)
@@ -208,51 +392,102 @@ func (r *ConnectorReconciler) mergePatchesIntoConfigs(ctx context.Context, logge
 	return configs, nil
 }
 
-func (r *ConnectorReconciler) createConnectorDeployment(ctx context.Context, connector *v1beta1.Connector, stream, configMapName string) error {
-	name := v1beta1.GenConnectorDeploymentName(connector.Name, stream)
-	containerPorts := []corev1.ContainerPort{
-		{
-			ContainerPort: v1beta1.ConnectorContainerPortMap[connector.Spec.Type],
+// connectorLabels returns the labels of the workloads serving a stream.
+func connectorLabels(connector *v1beta1.Connector, stream string) map[string]string {
+	return map[string]string{
+		hapi.ComponentKey:          v1beta1.ComponentTypeConnector,
+		hapi.InstanceKey:           connector.Name,
+		v1beta1.ConnectorStreamKey: stream,
+	}
+}
+
+// genConnectorConfigSecret generates the Secret holding the rendered config of
+// the connector for a stream, whose hash is recorded in the LastSpecKey
+// annotation. A Secret is used since the config may carry credentials
+// resolved from the secretKeyRef placeholders.
+func genConnectorConfigSecret(connector *v1beta1.Connector, stream, serviceURL string, config map[string]interface{}) (corev1.Secret, error) {
+	configWithService := map[string]interface{}{
+		"connector": config,
+		"hstream": map[string]string{
+			"serviceUrl": serviceURL,
 		},
 	}
 
-	if connector.Spec.Container.Ports != nil {
-		containerPorts = append(containerPorts, connector.Spec.Container.Ports...)
+	configJson, err := json.Marshal(configWithService)
+	if err != nil {
+		return corev1.Secret{}, err
+	}
+
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   connector.Namespace,
+			Name:        v1beta1.GenConnectorConfigSecretName(connector.Name, stream),
+			Labels:      connectorLabels(connector, stream),
+			Annotations: map[string]string{},
+		},
+		Data: map[string][]byte{
+			"config.json": configJson,
+		},
+	}
+	secret.Annotations[hapi.LastSpecKey] = internal.GetObjectHash(&secret)
+
+	return secret, nil
+}
+
+// genConnectorDeployment generates the Deployment running the connector for a
+// stream from the class of its type. The pod template carries the hash of the
+// config, so that the pods are rolled once the config changes.
+func genConnectorDeployment(connector *v1beta1.Connector, class *v1beta1.ConnectorClass, stream string, secret *corev1.Secret) appsv1.Deployment {
+	name := v1beta1.GenConnectorDeploymentName(connector.Name, stream)
+	container := *connector.Spec.Container.DeepCopy()
+	preconfiguredContainer := connectorgen.ClassContainer(connector, class, name, secret.Name)
+	containerPorts := preconfiguredContainer.Ports
+
+	if container.Ports != nil {
+		containerPorts = append(containerPorts, container.Ports...)
 	}
 	//nolint:staticcheck,SA1019 // this block is used to keep backward compatibility.
 	if connector.Spec.ContainerPorts != nil {
 		containerPorts = append(containerPorts, connector.Spec.ContainerPorts...)
 	}
 
-	connector.Spec.Container.Ports = containerPorts
-	preconfiguredContainer := connectorgen.DefaultSinkElasticsearchContainer(connector, name, configMapName)
-	structAssign(&preconfiguredContainer, &connector.Spec.Container)
+	container.Ports = containerPorts
+	structAssign(&preconfiguredContainer, &container)
+
+	podAnnotations := getPromAnnotations(connector)
+	podAnnotations[v1beta1.ConnectorConfigHashKey] = secret.Annotations[hapi.LastSpecKey]
+
+	dataVolume := corev1.VolumeSource{
+		EmptyDir: &corev1.EmptyDirVolumeSource{},
+	}
+	var strategy appsv1.DeploymentStrategy
+	if connector.Spec.Storage != nil {
+		dataVolume = corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: v1beta1.GenConnectorDataPVCName(connector.Name, stream),
+			},
+		}
+		// The old pod releases the volume, and stops updating the offsets,
+		// before the new pod starts.
+		strategy.Type = appsv1.RecreateDeploymentStrategyType
+	}
 
 	deployment := appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: connector.Namespace,
-			Name:      name,
-			Labels: map[string]string{
-				hapi.ComponentKey: v1beta1.ComponentTypeConnector,
-				hapi.InstanceKey:  connector.Name,
-			},
+			Namespace:   connector.Namespace,
+			Name:        name,
+			Labels:      connectorLabels(connector, stream),
+			Annotations: map[string]string{},
 		},
 		Spec: appsv1.DeploymentSpec{
 			Selector: &metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					hapi.ComponentKey: v1beta1.ComponentTypeConnector,
-					hapi.InstanceKey:  connector.Name,
-					"stream":          stream,
-				},
+				MatchLabels: connectorLabels(connector, stream),
 			},
+			Strategy: strategy,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
-						hapi.ComponentKey: v1beta1.ComponentTypeConnector,
-						hapi.InstanceKey:  connector.Name,
-						"stream":          stream,
-					},
-					Annotations: getPromAnnotations(connector),
+					Labels:      connectorLabels(connector, stream),
+					Annotations: podAnnotations,
 				},
 				Spec: corev1.PodSpec{
 					Containers: append(
@@ -263,32 +498,328 @@ func (r *ConnectorReconciler) createConnectorDeployment(ctx context.Context, con
 					),
 					Volumes: []corev1.Volume{
 						{
-							Name: configMapName,
+							Name: secret.Name,
 							VolumeSource: corev1.VolumeSource{
-								ConfigMap: &corev1.ConfigMapVolumeSource{
-									LocalObjectReference: corev1.LocalObjectReference{
-										Name: configMapName,
-									},
+								Secret: &corev1.SecretVolumeSource{
+									SecretName: secret.Name,
 								},
 							},
 						},
 						{
-							Name: "data",
-							VolumeSource: corev1.VolumeSource{
-								EmptyDir: &corev1.EmptyDirVolumeSource{},
-							},
+							Name:         "data",
+							VolumeSource: dataVolume,
 						},
 					},
 				},
 			},
 		},
 	}
+	deployment.Annotations[hapi.LastSpecKey] = internal.GetObjectHash(&deployment)
+
+	return deployment
+}
+
+// applyConnectorConfigSecret creates the config Secret, or updates it once its
+// hash changes.
+func (r *ConnectorReconciler) applyConnectorConfigSecret(ctx context.Context, connector *v1beta1.Connector, secret *corev1.Secret) error {
+	existing := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(secret), existing); err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return err
+		}
+		if err = controllerutil.SetControllerReference(connector, secret, r.Scheme); err != nil {
+			return err
+		}
+		return r.Create(ctx, secret)
+	}
+	if !isHashChanged(&existing.ObjectMeta, &secret.ObjectMeta) {
+		return nil
+	}
+
+	log.Info("Update connector config Secret", "Connector", connector.Name, "Secret", secret.Name)
+	if existing.Annotations == nil {
+		existing.Annotations = map[string]string{}
+	}
+	existing.Annotations[hapi.LastSpecKey] = secret.Annotations[hapi.LastSpecKey]
+	existing.Labels = secret.Labels
+	existing.Data = secret.Data
+	return r.Update(ctx, existing)
+}
+
+// genConnectorDataPVC generates the PersistentVolumeClaim keeping the state of
+// the connector for a stream.
+func genConnectorDataPVC(connector *v1beta1.Connector, stream string) corev1.PersistentVolumeClaim {
+	storage := connector.Spec.Storage
+	accessModes := storage.AccessModes
+	if len(accessModes) == 0 {
+		accessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+	}
+
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: connector.Namespace,
+			Name:      v1beta1.GenConnectorDataPVCName(connector.Name, stream),
+			Labels:    connectorLabels(connector, stream),
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      accessModes,
+			StorageClassName: storage.StorageClassName,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: storage.Size,
+				},
+			},
+		},
+	}
+}
+
+// applyConnectorDataPVC creates the data PVC of a stream, or adopts the one
+// retained before. Since the spec of a PVC is immutable, only the requested
+// size of an existing PVC is increased, and the other fields of the storage
+// which differ from the PVC are returned and reported by a Warning event.
+func (r *ConnectorReconciler) applyConnectorDataPVC(ctx context.Context, connector *v1beta1.Connector, pvc *corev1.PersistentVolumeClaim) ([]string, error) {
+	existing := &corev1.PersistentVolumeClaim{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(pvc), existing); err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return nil, err
+		}
+		if err = controllerutil.SetControllerReference(connector, pvc, r.Scheme); err != nil {
+			return nil, err
+		}
+		return nil, r.Create(ctx, pvc)
+	}
+
+	ignored := immutableDataPVCChanges(existing, pvc)
+	if len(ignored) > 0 {
+		r.Recorder.Eventf(connector, corev1.EventTypeWarning, v1beta1.ReasonStorageImmutable,
+			"Ignored the changes of %s of PersistentVolumeClaim %s, which are immutable", strings.Join(ignored, ", "), pvc.Name)
+	}
+
+	changed := false
+	if !metav1.IsControlledBy(existing, connector) {
+		// Adopt the PVC retained once the stream was removed.
+		log.Info("Adopt connector data PVC", "Connector", connector.Name, "PersistentVolumeClaim", pvc.Name)
+		if err := controllerutil.SetControllerReference(connector, existing, r.Scheme); err != nil {
+			return ignored, err
+		}
+		changed = true
+	}
+
+	requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	current := existing.Spec.Resources.Requests[corev1.ResourceStorage]
+	if requested.Cmp(current) > 0 {
+		log.Info("Expand connector data PVC", "Connector", connector.Name, "PersistentVolumeClaim", pvc.Name,
+			"from", current.String(), "to", requested.String())
+		if existing.Spec.Resources.Requests == nil {
+			existing.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		existing.Spec.Resources.Requests[corev1.ResourceStorage] = requested
+		changed = true
+	}
+
+	if !changed {
+		return ignored, nil
+	}
+	return ignored, r.Update(ctx, existing)
+}
+
+// immutableDataPVCChanges returns the immutable fields of the desired data PVC
+// which differ from the existing one. The storage class is only compared once
+// it is set, since the default one is filled in by the API server.
+func immutableDataPVCChanges(existing, desired *corev1.PersistentVolumeClaim) []string {
+	var fields []string
+	if desired.Spec.StorageClassName != nil &&
+		(existing.Spec.StorageClassName == nil || *existing.Spec.StorageClassName != *desired.Spec.StorageClassName) {
+		fields = append(fields, "storageClassName")
+	}
+	if !sameAccessModes(existing.Spec.AccessModes, desired.Spec.AccessModes) {
+		fields = append(fields, "accessModes")
+	}
+	return fields
+}
+
+func sameAccessModes(a, b []corev1.PersistentVolumeAccessMode) bool {
+	modes := make(map[corev1.PersistentVolumeAccessMode]bool, len(a))
+	for _, mode := range a {
+		modes[mode] = true
+	}
+	for _, mode := range b {
+		if !modes[mode] {
+			return false
+		}
+		delete(modes, mode)
+	}
+	return len(modes) == 0
+}
+
+// releaseConnectorDataPVC removes the owner reference of the connector from the
+// data PVC, so that the PVC is kept once the connector is deleted.
+func (r *ConnectorReconciler) releaseConnectorDataPVC(ctx context.Context, connector *v1beta1.Connector, pvc *corev1.PersistentVolumeClaim) error {
+	refs := make([]metav1.OwnerReference, 0, len(pvc.OwnerReferences))
+	for _, ref := range pvc.OwnerReferences {
+		if ref.UID != connector.UID {
+			refs = append(refs, ref)
+		}
+	}
+	pvc.OwnerReferences = refs
+	return client.IgnoreNotFound(r.Update(ctx, pvc))
+}
+
+// applyConnectorDeployment creates the Deployment, or updates it once its hash
+// changes. The deployment is replaced by the one observed in the cluster.
+func (r *ConnectorReconciler) applyConnectorDeployment(ctx context.Context, connector *v1beta1.Connector, deployment *appsv1.Deployment) error {
+	existing := &appsv1.Deployment{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(deployment), existing); err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return err
+		}
+		if err = controllerutil.SetControllerReference(connector, deployment, r.Scheme); err != nil {
+			return err
+		}
+		return r.Create(ctx, deployment)
+	}
+	if !isHashChanged(&existing.ObjectMeta, &deployment.ObjectMeta) {
+		*deployment = *existing
+		return nil
+	}
+
+	log.Info("Update connector Deployment", "Connector", connector.Name, "Deployment", deployment.Name)
+	if existing.Annotations == nil {
+		existing.Annotations = map[string]string{}
+	}
+	existing.Annotations[hapi.LastSpecKey] = deployment.Annotations[hapi.LastSpecKey]
+	existing.Labels = deployment.Labels
+	existing.Spec.Strategy = deployment.Spec.Strategy
+	existing.Spec.Template = deployment.Spec.Template
+	if err := r.Update(ctx, existing); err != nil {
+		return err
+	}
+	*deployment = *existing
+	return nil
+}
+
+// removeStaleConnectorResources deletes the Deployments and config Secrets
+// owned by the connector for the streams no longer in its spec, as well as the
+// config Secrets of an outdated name and the ConfigMaps which held the configs
+// before they were moved into Secrets. The legacy ConfigMaps carry no labels, so
+// they are found by their controller reference. The data PVCs of the removed
+// streams are deleted with the Delete retention policy, and are otherwise
+// released from the connector, as are all of them once the storage is unset.
+func (r *ConnectorReconciler) removeStaleConnectorResources(ctx context.Context, connector *v1beta1.Connector) error {
+	desired := make(map[string]bool, len(connector.Spec.Streams))
+	for _, stream := range connector.Spec.Streams {
+		desired[stream] = true
+	}
+	isStale := func(obj client.Object) bool {
+		return metav1.IsControlledBy(obj, connector) && !desired[obj.GetLabels()[v1beta1.ConnectorStreamKey]]
+	}
+	listOpts := []client.ListOption{
+		client.InNamespace(connector.Namespace),
+		client.MatchingLabels{
+			hapi.ComponentKey: v1beta1.ComponentTypeConnector,
+			hapi.InstanceKey:  connector.Name,
+		},
+		client.HasLabels{v1beta1.ConnectorStreamKey},
+	}
+
+	var deployments appsv1.DeploymentList
+	if err := r.List(ctx, &deployments, listOpts...); err != nil {
+		return err
+	}
+	for i := range deployments.Items {
+		if err := r.removeStaleConnectorResource(ctx, connector, &deployments.Items[i], isStale); err != nil {
+			return err
+		}
+	}
 
-	if err := controllerutil.SetControllerReference(connector, &deployment, r.Scheme); err != nil {
+	var secrets corev1.SecretList
+	if err := r.List(ctx, &secrets, listOpts...); err != nil {
 		return err
 	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		stream := secret.Labels[v1beta1.ConnectorStreamKey]
+		if !isStale(secret) && metav1.IsControlledBy(secret, connector) &&
+			secret.Name != v1beta1.GenConnectorConfigSecretName(connector.Name, stream) {
+			log.Info("Delete connector config Secret of an outdated name", "Connector", connector.Name, "Secret", secret.Name)
+			if err := r.Delete(ctx, secret); client.IgnoreNotFound(err) != nil {
+				return err
+			}
+			continue
+		}
+		if err := r.removeStaleConnectorResource(ctx, connector, secret, isStale); err != nil {
+			return err
+		}
+	}
 
-	return r.Create(ctx, &deployment)
+	var pvcs corev1.PersistentVolumeClaimList
+	if err := r.List(ctx, &pvcs, listOpts...); err != nil {
+		return err
+	}
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		switch {
+		case !metav1.IsControlledBy(pvc, connector):
+			continue
+		case connector.Spec.Storage == nil:
+			log.Info("Retain connector data PVC since the storage is unset", "Connector", connector.Name, "PersistentVolumeClaim", pvc.Name)
+			if err := r.releaseConnectorDataPVC(ctx, connector, pvc); err != nil {
+				return err
+			}
+		case connector.Spec.Storage.RetentionPolicy != v1beta1.ConnectorStorageDelete && isStale(pvc):
+			stream := pvc.Labels[v1beta1.ConnectorStreamKey]
+			log.Info("Retain connector data PVC of removed stream", "Connector", connector.Name, "PersistentVolumeClaim", pvc.Name)
+			if err := r.releaseConnectorDataPVC(ctx, connector, pvc); err != nil {
+				return err
+			}
+			r.Recorder.Eventf(connector, corev1.EventTypeNormal, v1beta1.ReasonStreamRemoved,
+				"Retained PersistentVolumeClaim %s of the removed stream %s", pvc.Name, stream)
+		default:
+			if err := r.removeStaleConnectorResource(ctx, connector, pvc, isStale); err != nil {
+				return err
+			}
+		}
+	}
+
+	var configMaps corev1.ConfigMapList
+	if err := r.List(ctx, &configMaps, client.InNamespace(connector.Namespace)); err != nil {
+		return err
+	}
+	for i := range configMaps.Items {
+		configMap := &configMaps.Items[i]
+		if !metav1.IsControlledBy(configMap, connector) {
+			continue
+		}
+		log.Info("Delete legacy connector ConfigMap", "Connector", connector.Name, "ConfigMap", configMap.Name)
+		if err := r.Delete(ctx, configMap); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ConnectorReconciler) removeStaleConnectorResource(ctx context.Context, connector *v1beta1.Connector,
+	obj client.Object, isStale func(client.Object) bool) error {
+	if !isStale(obj) {
+		return nil
+	}
+
+	kind := "Deployment"
+	switch obj.(type) {
+	case *corev1.Secret:
+		kind = "Secret"
+	case *corev1.PersistentVolumeClaim:
+		kind = "PersistentVolumeClaim"
+	}
+	stream := obj.GetLabels()[v1beta1.ConnectorStreamKey]
+
+	log.Info("Delete connector resource of removed stream", "Connector", connector.Name, kind, obj.GetName())
+	if err := r.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	r.Recorder.Eventf(connector, corev1.EventTypeNormal, v1beta1.ReasonStreamRemoved,
+		"Deleted %s %s of the removed stream %s", kind, obj.GetName(), stream)
+	return nil
 }
 
 func getPromAnnotations(connector *v1beta1.Connector) (annotaions map[string]string) {
@@ -19,14 +19,18 @@ package controller
 import (
 	"context"
 
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
 	"github.com/hstreamdb/hstream-operator/mock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/hstreamdb/hstream-operator/api/v1beta1"
 )
@@ -49,19 +53,19 @@ var _ = Describe("controller/connector", Ordered, func() {
 	It("should create/delete a connector successfully", func() {
 		connector := mock.CreateDefaultConnector("connector-test")
 		connector.Name = connector.Name + "-1"
-		var configMap corev1.ConfigMap
+		var secret corev1.Secret
 		var deployment appsv1.Deployment
-		configMapName, deploymentName := getConnectorSubResourceName(&connector)
+		secretName, deploymentName := getConnectorSubResourceName(&connector)
 
 		By("creating a connector")
 		Expect(k8sClient.Create(context.TODO(), &connector)).Should(Succeed())
 
-		By("check if the connector's configmap is generated")
+		By("check if the connector's config secret is generated")
 		Eventually(func() error {
 			return k8sClient.Get(context.TODO(), types.NamespacedName{
-				Name:      configMapName,
+				Name:      secretName,
 				Namespace: connector.Namespace,
-			}, &configMap)
+			}, &secret)
 		}).Should(BeNil())
 
 		By("check if the connector's deployment is generated")
@@ -81,8 +85,8 @@ var _ = Describe("controller/connector", Ordered, func() {
 			BlockOwnerDeletion: &[]bool{true}[0],
 		}
 
-		By("check if the owner reference of the configmap is set")
-		Expect(configMap.OwnerReferences).To(ContainElement(expectedOwnerReference))
+		By("check if the owner reference of the config secret is set")
+		Expect(secret.OwnerReferences).To(ContainElement(expectedOwnerReference))
 
 		By("check if the owner reference of the deployment is set")
 		Expect(deployment.OwnerReferences).To(ContainElement(expectedOwnerReference))
@@ -127,9 +131,233 @@ var _ = Describe("controller/connector", Ordered, func() {
 		Expect(deployment.Spec.Template.Spec.Containers[0].Ports).To(ContainElement(connector.Spec.Container.Ports[0]))
 		Expect(deployment.Spec.Template.Spec.Containers[0].Resources).To(Equal(connector.Spec.Container.Resources))
 	})
+
+	It("should update the connector's config secret and deployment", func() {
+		connector := mock.CreateDefaultConnector("connector-test")
+		connector.Name = connector.Name + "-3"
+		var secret corev1.Secret
+		var deployment appsv1.Deployment
+		secretName, deploymentName := getConnectorSubResourceName(&connector)
+
+		By("creating a connector")
+		Expect(k8sClient.Create(context.TODO(), &connector)).Should(Succeed())
+		Eventually(func() error {
+			return k8sClient.Get(context.TODO(), types.NamespacedName{
+				Name:      deploymentName,
+				Namespace: connector.Namespace,
+			}, &deployment)
+		}).Should(BeNil())
+		configHash := deployment.Spec.Template.Annotations[v1beta1.ConnectorConfigHashKey]
+
+		By("updating the patches and the image registry")
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(&connector), &connector)).Should(Succeed())
+		connector.Spec.Patches = []byte(`{"stream01": {"index": "index02"}}`)
+		connector.Spec.ImageRegistry = &[]string{"registry.example.com"}[0]
+		Expect(k8sClient.Update(context.TODO(), &connector)).Should(Succeed())
+
+		Eventually(func() string {
+			_ = k8sClient.Get(context.TODO(), types.NamespacedName{
+				Name:      secretName,
+				Namespace: connector.Namespace,
+			}, &secret)
+			return string(secret.Data["config.json"])
+		}).Should(ContainSubstring(`"index":"index02"`))
+
+		Eventually(func() string {
+			_ = k8sClient.Get(context.TODO(), types.NamespacedName{
+				Name:      deploymentName,
+				Namespace: connector.Namespace,
+			}, &deployment)
+			return deployment.Spec.Template.Spec.Containers[0].Image
+		}).Should(HavePrefix("registry.example.com/"))
+		Expect(deployment.Spec.Template.Annotations[v1beta1.ConnectorConfigHashKey]).NotTo(Equal(configHash))
+	})
+
+	It("should delete the resources of removed streams", func() {
+		connector := mock.CreateDefaultConnector("connector-test")
+		connector.Name = connector.Name + "-4"
+		connector.Spec.Streams = []string{"stream01", "stream_02"}
+		removedDeployment := types.NamespacedName{
+			Name:      v1beta1.GenConnectorDeploymentName(connector.Name, "stream_02"),
+			Namespace: connector.Namespace,
+		}
+		removedSecret := types.NamespacedName{
+			Name:      v1beta1.GenConnectorConfigSecretName(connector.Name, "stream_02"),
+			Namespace: connector.Namespace,
+		}
+
+		By("creating a connector with two streams")
+		Expect(k8sClient.Create(context.TODO(), &connector)).Should(Succeed())
+		Eventually(func() error {
+			return k8sClient.Get(context.TODO(), removedDeployment, &appsv1.Deployment{})
+		}).Should(BeNil())
+
+		By("removing a stream")
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(&connector), &connector)).Should(Succeed())
+		connector.Spec.Streams = []string{"stream01"}
+		Expect(k8sClient.Update(context.TODO(), &connector)).Should(Succeed())
+
+		Eventually(func() bool {
+			return k8sErrors.IsNotFound(k8sClient.Get(context.TODO(), removedDeployment, &appsv1.Deployment{}))
+		}).Should(BeTrue())
+		Eventually(func() bool {
+			return k8sErrors.IsNotFound(k8sClient.Get(context.TODO(), removedSecret, &corev1.Secret{}))
+		}).Should(BeTrue())
+
+		_, deploymentName := getConnectorSubResourceName(&connector)
+		Expect(k8sClient.Get(context.TODO(), types.NamespacedName{
+			Name:      deploymentName,
+			Namespace: connector.Namespace,
+		}, &appsv1.Deployment{})).Should(Succeed())
+	})
+
+	It("should re-render the config once the template changes", func() {
+		connector := mock.CreateDefaultConnector("connector-test")
+		connector.Name = connector.Name + "-5"
+		var secret corev1.Secret
+		secretName, _ := getConnectorSubResourceName(&connector)
+
+		By("creating a connector")
+		Expect(k8sClient.Create(context.TODO(), &connector)).Should(Succeed())
+		Eventually(func() error {
+			return k8sClient.Get(context.TODO(), types.NamespacedName{
+				Name:      secretName,
+				Namespace: connector.Namespace,
+			}, &secret)
+		}).Should(BeNil())
+
+		By("updating the template")
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(&connectorTpl), &connectorTpl)).Should(Succeed())
+		connectorTpl.Spec.Config = `{"hosts": "elasticsearch:9200"}`
+		Expect(k8sClient.Update(context.TODO(), &connectorTpl)).Should(Succeed())
+
+		Eventually(func() string {
+			_ = k8sClient.Get(context.TODO(), types.NamespacedName{
+				Name:      secretName,
+				Namespace: connector.Namespace,
+			}, &secret)
+			return string(secret.Data["config.json"])
+		}).Should(ContainSubstring(`"hosts":"elasticsearch:9200"`))
+	})
+
+	It("should wait for the referred HStreamDB", func() {
+		connector := mock.CreateDefaultConnector("connector-test")
+		connector.Name = connector.Name + "-6"
+		connector.Spec.HServerEndpoint = ""
+		connector.Spec.HStreamDBRef = &v1beta1.HStreamDBReference{HStreamDBReference: hapi.HStreamDBReference{Name: "connector-hdb"}}
+		secretName, deploymentName := getConnectorSubResourceName(&connector)
+
+		By("creating a connector referring to a missing HStreamDB")
+		Expect(k8sClient.Create(context.TODO(), &connector)).Should(Succeed())
+		Eventually(func() string {
+			_ = k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(&connector), &connector)
+			if condition := meta.FindStatusCondition(connector.Status.Conditions, hapi.Ready); condition != nil {
+				return condition.Reason
+			}
+			return ""
+		}).Should(Equal(hapi.ReasonHStreamDBNotFound))
+		Expect(k8sErrors.IsNotFound(k8sClient.Get(context.TODO(), types.NamespacedName{
+			Name:      deploymentName,
+			Namespace: connector.Namespace,
+		}, &appsv1.Deployment{}))).To(BeTrue())
+
+		By("creating the HStreamDB and making it ready")
+		hdb := mock.CreateDefaultCR()
+		hdb.Name = "connector-hdb"
+		hdb.Namespace = connector.Namespace
+		hdb.Spec.HServer.Container.Args = []string{"--enable-tls"}
+		Expect(k8sClient.Create(context.TODO(), hdb)).Should(Succeed())
+		hdb.SetCondition(metav1.Condition{Type: hapi.Ready, Status: metav1.ConditionTrue, Reason: "test"})
+		Expect(k8sClient.Status().Update(context.TODO(), hdb)).Should(Succeed())
+
+		var secret corev1.Secret
+		Eventually(func() string {
+			_ = k8sClient.Get(context.TODO(), types.NamespacedName{
+				Name:      secretName,
+				Namespace: connector.Namespace,
+			}, &secret)
+			return string(secret.Data["config.json"])
+		}).Should(ContainSubstring(`"serviceUrl":"hstreams://connector-hdb-internal-hserver.connector-test:6570"`))
+		Expect(k8sClient.Get(context.TODO(), types.NamespacedName{
+			Name:      deploymentName,
+			Namespace: connector.Namespace,
+		}, &appsv1.Deployment{})).Should(Succeed())
+	})
+
+	It("should resolve the secret references and roll once the secret changes", func() {
+		connector := mock.CreateDefaultConnector("connector-test")
+		connector.Name = connector.Name + "-7"
+		connector.Spec.Patches = []byte(`{"stream01": {"password": {"valueFrom": {"secretKeyRef": {"name": "es-auth", "key": "password"}}}}}`)
+		var secret corev1.Secret
+		var deployment appsv1.Deployment
+		secretName, deploymentName := getConnectorSubResourceName(&connector)
+
+		By("creating a connector referring to a missing secret")
+		Expect(k8sClient.Create(context.TODO(), &connector)).Should(Succeed())
+		Eventually(func() string {
+			_ = k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(&connector), &connector)
+			if len(connector.Status.Streams) == 0 {
+				return ""
+			}
+			return connector.Status.Streams[0].Error
+		}).Should(ContainSubstring("es-auth"))
+
+		By("creating the secret")
+		credentials := corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "es-auth", Namespace: connector.Namespace},
+			Data:       map[string][]byte{"password": []byte("secret01")},
+		}
+		Expect(k8sClient.Create(context.TODO(), &credentials)).Should(Succeed())
+		Eventually(func() string {
+			_ = k8sClient.Get(context.TODO(), types.NamespacedName{
+				Name:      secretName,
+				Namespace: connector.Namespace,
+			}, &secret)
+			return string(secret.Data["config.json"])
+		}).Should(ContainSubstring(`"password":"secret01"`))
+		Eventually(func() error {
+			return k8sClient.Get(context.TODO(), types.NamespacedName{
+				Name:      deploymentName,
+				Namespace: connector.Namespace,
+			}, &deployment)
+		}).Should(BeNil())
+		configHash := deployment.Spec.Template.Annotations[v1beta1.ConnectorConfigHashKey]
+
+		By("rotating the password")
+		credentials.Data["password"] = []byte("secret02")
+		Expect(k8sClient.Update(context.TODO(), &credentials)).Should(Succeed())
+		Eventually(func() string {
+			_ = k8sClient.Get(context.TODO(), types.NamespacedName{
+				Name:      deploymentName,
+				Namespace: connector.Namespace,
+			}, &deployment)
+			return deployment.Spec.Template.Annotations[v1beta1.ConnectorConfigHashKey]
+		}).ShouldNot(Equal(configHash))
+	})
+
+	It("should report the streams whose config is invalid", func() {
+		connector := mock.CreateDefaultConnector("connector-test")
+		connector.Name = connector.Name + "-8"
+		connector.Spec.Patches = []byte(`{"stream01": {"scheme": "ftp"}}`)
+		_, deploymentName := getConnectorSubResourceName(&connector)
+
+		Expect(k8sClient.Create(context.TODO(), &connector)).Should(Succeed())
+		Eventually(func() string {
+			_ = k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(&connector), &connector)
+			if condition := meta.FindStatusCondition(connector.Status.Conditions, v1beta1.ConnectorConfigValid); condition != nil {
+				return condition.Reason
+			}
+			return ""
+		}).Should(Equal(v1beta1.ReasonConfigInvalid))
+		Expect(connector.Status.Streams[0].Error).To(ContainSubstring("scheme in body should be one of [http https]"))
+		Expect(k8sErrors.IsNotFound(k8sClient.Get(context.TODO(), types.NamespacedName{
+			Name:      deploymentName,
+			Namespace: connector.Namespace,
+		}, &appsv1.Deployment{}))).To(BeTrue())
+	})
 })
 
 func getConnectorSubResourceName(connector *v1beta1.Connector) (string, string) {
-	return v1beta1.GenConnectorConfigMapNameForStream(connector.Name, connector.Spec.Streams[0]),
+	return v1beta1.GenConnectorConfigSecretName(connector.Name, connector.Spec.Streams[0]),
 		v1beta1.GenConnectorDeploymentName(connector.Name, connector.Spec.Streams[0])
 }
@@ -1,9 +1,28 @@
 package controller
 
 import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"github.com/hstreamdb/hstream-operator/api/v1beta1"
 	"github.com/hstreamdb/hstream-operator/mock"
+	"github.com/hstreamdb/hstream-operator/pkg/connectorgen"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var _ = Describe("controller/connector/unit", func() {
@@ -19,4 +38,397 @@ var _ = Describe("controller/connector/unit", func() {
 			"prometheus.io/scrape": "true",
 		}))
 	})
+
+	It("should roll the deployment once the config changes", func() {
+		connector := mock.CreateDefaultConnector("default")
+		connector.Spec.Streams = []string{"stream01", "stream02"}
+		connector.Spec.Container.Ports = []corev1.ContainerPort{{Name: "prom", ContainerPort: 9400}}
+
+		secret, err := genConnectorConfigSecret(&connector, "stream01", "hstream://hserver:6570", map[string]interface{}{"index": "index01"})
+		Expect(err).To(Succeed())
+		Expect(string(secret.Data["config.json"])).To(ContainSubstring(`"serviceUrl":"hstream://hserver:6570"`))
+
+		class, ok := connectorgen.BuiltinConnectorClass(connector.Spec.Type)
+		Expect(ok).To(BeTrue())
+
+		deployment := genConnectorDeployment(&connector, class, "stream01", &secret)
+		Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue(
+			v1beta1.ConnectorConfigHashKey, secret.Annotations[hapi.LastSpecKey]))
+		Expect(deployment.Spec.Template.Spec.Volumes[0].Secret.SecretName).To(Equal(secret.Name))
+
+		By("not accumulating the ports of the streams")
+		other := genConnectorDeployment(&connector, class, "stream02", &secret)
+		Expect(other.Spec.Template.Spec.Containers[0].Ports).To(HaveLen(2))
+		Expect(connector.Spec.Container.Ports).To(HaveLen(1))
+
+		By("changing the hash of the config and the deployment")
+		changed, err := genConnectorConfigSecret(&connector, "stream01", "hstream://hserver:6570", map[string]interface{}{"index": "index02"})
+		Expect(err).To(Succeed())
+		Expect(isHashChanged(&secret.ObjectMeta, &changed.ObjectMeta)).To(BeTrue())
+
+		rolled := genConnectorDeployment(&connector, class, "stream01", &changed)
+		Expect(isHashChanged(&deployment.ObjectMeta, &rolled.ObjectMeta)).To(BeTrue())
+		Expect(rolled.Spec.Template.Annotations[v1beta1.ConnectorConfigHashKey]).
+			NotTo(Equal(deployment.Spec.Template.Annotations[v1beta1.ConnectorConfigHashKey]))
+	})
+
+	It("should run the connector with the image and ports of its class", func() {
+		connector := mock.CreateDefaultConnector("default")
+		connector.Spec.Type = "sink-inhouse"
+		connector.Spec.Container.Ports = []corev1.ContainerPort{{Name: "prom", ContainerPort: 9400}}
+		class := &v1beta1.ConnectorClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "sink-inhouse"},
+			Spec: v1beta1.ConnectorClassSpec{
+				Image:           "example/sink-inhouse:v1",
+				Ports:           []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+				ConfigMountPath: "/etc/connector",
+			},
+		}
+
+		secret, err := genConnectorConfigSecret(&connector, "stream01", "hstream://hserver:6570", map[string]interface{}{})
+		Expect(err).To(Succeed())
+		container := genConnectorDeployment(&connector, class, "stream01", &secret).Spec.Template.Spec.Containers[0]
+		Expect(container.Image).To(Equal("example/sink-inhouse:v1"))
+		Expect(container.Args).To(ContainElement("--config /etc/connector/config.json"))
+		Expect(container.Ports).To(Equal([]corev1.ContainerPort{
+			{Name: "http", ContainerPort: 8080},
+			{Name: "prom", ContainerPort: 9400},
+		}))
+		Expect(class.Spec.Ports).To(HaveLen(1))
+	})
+
+	It("should fail to resolve HServer without an endpoint or an HStreamDB", func() {
+		r := &ConnectorReconciler{}
+		connector := mock.CreateDefaultConnector("default")
+		url, pending, err := r.resolveHServerURL(context.TODO(), &connector)
+		Expect(err).To(Succeed())
+		Expect(pending).To(BeNil())
+		Expect(url).To(Equal("hstream://hstreamdb-hserver:6570"))
+
+		connector.Spec.HServerEndpoint = ""
+		_, _, err = r.resolveHServerURL(context.TODO(), &connector)
+		Expect(err).To(MatchError(errNoHServerEndpoint))
+	})
+
+	It("should apply the global patch and the patch of each stream", func() {
+		scheme := runtime.NewScheme()
+		Expect(v1beta1.AddToScheme(scheme)).To(Succeed())
+		template := mock.CreateDefaultConnectorTemplate()
+		template.Spec.Config = `{"hosts": ["es-0:9200"], "auth": {"username": "elastic", "password": "changeme"}}`
+		r := &ConnectorReconciler{Client: clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(&template).Build()}
+
+		connector := mock.CreateDefaultConnector("default")
+		connector.Spec.Streams = []string{"stream01", "stream02"}
+		connector.Spec.GlobalPatch = []byte(`{"auth": {"password": "secret"}, "index": "global"}`)
+		connector.Spec.Patches = []byte(`{"stream02": {"hosts": ["es-1:9200"], "index": null}}`)
+
+		configs, err := r.mergePatchesIntoConfigs(context.TODO(), logr.Discard(), connector)
+		Expect(err).To(Succeed())
+		Expect(configs[0]).To(Equal(map[string]interface{}{
+			"stream": "stream01",
+			"hosts":  []interface{}{"es-0:9200"},
+			"auth":   map[string]interface{}{"username": "elastic", "password": "secret"},
+			"index":  "global",
+		}))
+		Expect(configs[1]).To(Equal(map[string]interface{}{
+			"stream": "stream02",
+			"hosts":  []interface{}{"es-1:9200"},
+			"auth":   map[string]interface{}{"username": "elastic", "password": "secret"},
+		}))
+
+		By("applying JSON Patches")
+		connector.Spec.PatchType = v1beta1.ConnectorPatchJSON
+		connector.Spec.GlobalPatch = []byte(`[{"op": "add", "path": "/hosts/-", "value": "es-1:9200"}]`)
+		connector.Spec.Patches = []byte(`{"stream02": [{"op": "remove", "path": "/auth/password"}]}`)
+
+		configs, err = r.mergePatchesIntoConfigs(context.TODO(), logr.Discard(), connector)
+		Expect(err).To(Succeed())
+		Expect(configs[0]["hosts"]).To(Equal([]interface{}{"es-0:9200", "es-1:9200"}))
+		Expect(configs[0]["auth"]).To(HaveKey("password"))
+		Expect(configs[1]["hosts"]).To(Equal([]interface{}{"es-0:9200", "es-1:9200"}))
+		Expect(configs[1]["auth"]).To(Equal(map[string]interface{}{"username": "elastic"}))
+
+		connector.Spec.Patches = []byte(`{"stream02": {"index": "index02"}}`)
+		_, err = r.mergePatchesIntoConfigs(context.TODO(), logr.Discard(), connector)
+		Expect(err).To(MatchError(ContainSubstring("invalid patch of stream stream02")))
+	})
+
+	It("should keep the state of the connector in a PVC of each stream", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(v1beta1.AddToScheme(scheme)).To(Succeed())
+		r := &ConnectorReconciler{
+			Client:   clientfake.NewClientBuilder().WithScheme(scheme).Build(),
+			Scheme:   scheme,
+			Recorder: record.NewFakeRecorder(10),
+		}
+
+		connector := mock.CreateDefaultConnector("default")
+		connector.UID = "connector-uid"
+		connector.Spec.Streams = []string{"stream01", "stream02"}
+		class, _ := connectorgen.BuiltinConnectorClass(connector.Spec.Type)
+		secret, err := genConnectorConfigSecret(&connector, "stream01", "hstream://hserver:6570", map[string]interface{}{})
+		Expect(err).To(Succeed())
+
+		By("using an emptyDir volume without storage")
+		deployment := genConnectorDeployment(&connector, class, "stream01", &secret)
+		Expect(deployment.Spec.Template.Spec.Volumes[1].EmptyDir).NotTo(BeNil())
+		Expect(deployment.Spec.Strategy.Type).To(BeEmpty())
+
+		connector.Spec.Storage = &v1beta1.ConnectorStorage{Size: resource.MustParse("1Gi")}
+		deployment = genConnectorDeployment(&connector, class, "stream01", &secret)
+		Expect(deployment.Spec.Template.Spec.Volumes[1].PersistentVolumeClaim.ClaimName).
+			To(Equal(v1beta1.GenConnectorDataPVCName(connector.Name, "stream01")))
+		Expect(deployment.Spec.Strategy.Type).To(Equal(appsv1.RecreateDeploymentStrategyType))
+		Expect(deployment.Spec.Template.Spec.Containers[0].VolumeMounts).
+			To(ContainElement(corev1.VolumeMount{Name: "data", MountPath: "/data"}))
+
+		for _, stream := range connector.Spec.Streams {
+			pvc := genConnectorDataPVC(&connector, stream)
+			Expect(pvc.Spec.AccessModes).To(Equal([]corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}))
+			Expect(r.applyConnectorDataPVC(context.TODO(), &connector, &pvc)).To(BeEmpty())
+		}
+
+		By("only increasing the requested size")
+		key := types.NamespacedName{Namespace: "default", Name: v1beta1.GenConnectorDataPVCName(connector.Name, "stream01")}
+		for _, size := range []string{"2Gi", "512Mi"} {
+			connector.Spec.Storage.Size = resource.MustParse(size)
+			pvc := genConnectorDataPVC(&connector, "stream01")
+			Expect(r.applyConnectorDataPVC(context.TODO(), &connector, &pvc)).To(BeEmpty())
+		}
+		var pvc corev1.PersistentVolumeClaim
+		Expect(r.Get(context.TODO(), key, &pvc)).To(Succeed())
+		Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("2Gi"))
+
+		By("reporting the changes of the immutable fields")
+		connector.Spec.Storage.StorageClassName = pointer.String("fast")
+		connector.Spec.Storage.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}
+		pvc = genConnectorDataPVC(&connector, "stream01")
+		ignored, err := r.applyConnectorDataPVC(context.TODO(), &connector, &pvc)
+		Expect(err).To(Succeed())
+		Expect(ignored).To(Equal([]string{"storageClassName", "accessModes"}))
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("Warning StorageImmutable")))
+		status := newConnectorStatus(&connector, []connectorStream{{
+			status:               v1beta1.ConnectorStreamStatus{Stream: "stream01"},
+			ignoredStorageFields: ignored,
+		}}, nil)
+		condition := meta.FindStatusCondition(status.Conditions, v1beta1.ConnectorStorageSynced)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(v1beta1.ReasonStorageImmutable))
+		Expect(condition.Message).To(ContainSubstring("stream01 (storageClassName, accessModes)"))
+		connector.Spec.Storage.StorageClassName = nil
+		connector.Spec.Storage.AccessModes = nil
+
+		By("retaining the PVCs of the removed streams by default")
+		removedKey := types.NamespacedName{Namespace: "default", Name: v1beta1.GenConnectorDataPVCName(connector.Name, "stream02")}
+		connector.Spec.Streams = []string{"stream01"}
+		Expect(r.removeStaleConnectorResources(context.TODO(), &connector)).To(Succeed())
+		Expect(r.Get(context.TODO(), removedKey, &pvc)).To(Succeed())
+		Expect(pvc.OwnerReferences).To(BeEmpty())
+		Expect(r.Get(context.TODO(), key, &pvc)).To(Succeed())
+		Expect(metav1.IsControlledBy(&pvc, &connector)).To(BeTrue())
+
+		By("adopting the retained PVC once the stream is added back")
+		connector.Spec.Streams = []string{"stream01", "stream02"}
+		pvc = genConnectorDataPVC(&connector, "stream02")
+		Expect(r.applyConnectorDataPVC(context.TODO(), &connector, &pvc)).To(BeEmpty())
+		Expect(r.Get(context.TODO(), removedKey, &pvc)).To(Succeed())
+		Expect(metav1.IsControlledBy(&pvc, &connector)).To(BeTrue())
+
+		By("deleting the PVCs of the removed streams with the Delete retention policy")
+		connector.Spec.Storage.RetentionPolicy = v1beta1.ConnectorStorageDelete
+		connector.Spec.Streams = []string{"stream01"}
+		Expect(r.removeStaleConnectorResources(context.TODO(), &connector)).To(Succeed())
+		var pvcs corev1.PersistentVolumeClaimList
+		Expect(r.List(context.TODO(), &pvcs)).To(Succeed())
+		Expect(pvcs.Items).To(HaveLen(1))
+		Expect(pvcs.Items[0].Name).To(Equal(key.Name))
+
+		By("retaining the PVCs once the storage is unset")
+		connector.Spec.Storage = nil
+		Expect(r.removeStaleConnectorResources(context.TODO(), &connector)).To(Succeed())
+		Expect(r.List(context.TODO(), &pvcs)).To(Succeed())
+		Expect(pvcs.Items).To(HaveLen(1))
+		Expect(pvcs.Items[0].OwnerReferences).To(BeEmpty())
+	})
+
+	It("should delete the legacy ConfigMaps and the config Secrets of an outdated name", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(v1beta1.AddToScheme(scheme)).To(Succeed())
+		connector := mock.CreateDefaultConnector("default")
+		connector.UID = "connector-uid"
+		connector.Spec.Streams = []string{"stream01"}
+
+		legacyConfigMap := corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Namespace: connector.Namespace,
+			Name:      v1beta1.GenConnectorConfigMapNameForStream(connector.Name, "stream01"),
+		}}
+		unownedConfigMap := corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Namespace: connector.Namespace,
+			Name:      "unowned",
+		}}
+		outdatedSecret := corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Namespace: connector.Namespace,
+			Name:      v1beta1.GenConnectorConfigMapNameForStream(connector.Name, "stream01"),
+			Labels:    connectorLabels(&connector, "stream01"),
+		}}
+		secret, err := genConnectorConfigSecret(&connector, "stream01", "hstream://hserver:6570", map[string]interface{}{})
+		Expect(err).To(Succeed())
+		for _, obj := range []metav1.Object{&legacyConfigMap, &outdatedSecret, &secret} {
+			Expect(ctrl.SetControllerReference(&connector, obj, scheme)).To(Succeed())
+		}
+
+		r := &ConnectorReconciler{
+			Client: clientfake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(&legacyConfigMap, &unownedConfigMap, &outdatedSecret, &secret).Build(),
+			Scheme:   scheme,
+			Recorder: record.NewFakeRecorder(10),
+		}
+		Expect(r.removeStaleConnectorResources(context.TODO(), &connector)).To(Succeed())
+
+		var configMaps corev1.ConfigMapList
+		Expect(r.List(context.TODO(), &configMaps)).To(Succeed())
+		Expect(configMaps.Items).To(HaveLen(1))
+		Expect(configMaps.Items[0].Name).To(Equal(unownedConfigMap.Name))
+		var secrets corev1.SecretList
+		Expect(r.List(context.TODO(), &secrets)).To(Succeed())
+		Expect(secrets.Items).To(HaveLen(1))
+		Expect(secrets.Items[0].Name).To(Equal(v1beta1.GenConnectorConfigSecretName(connector.Name, "stream01")))
+	})
+
+	It("should resolve the secret references in the config", func() {
+		c := clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "es-auth", Namespace: "default"},
+			Data:       map[string][]byte{"username": []byte("elastic"), "password": []byte("changeme")},
+		}).Build()
+		ref := func(name, key string) map[string]interface{} {
+			return map[string]interface{}{"valueFrom": map[string]interface{}{
+				"secretKeyRef": map[string]interface{}{"name": name, "key": key},
+			}}
+		}
+		config := map[string]interface{}{
+			"index": "index01",
+			"auth":  map[string]interface{}{"username": ref("es-auth", "username")},
+			"hosts": []interface{}{"es:9200", ref("es-auth", "password")},
+		}
+
+		resolved, err := resolveSecretRefs(context.TODO(), c, "default", config)
+		Expect(err).To(Succeed())
+		Expect(resolved).To(Equal(map[string]interface{}{
+			"index": "index01",
+			"auth":  map[string]interface{}{"username": "elastic"},
+			"hosts": []interface{}{"es:9200", "changeme"},
+		}))
+		Expect(config["auth"]).To(HaveKeyWithValue("username", ref("es-auth", "username")))
+
+		names := map[string]bool{}
+		referencedSecrets(config, names)
+		Expect(names).To(Equal(map[string]bool{"es-auth": true}))
+
+		By("failing on a missing key or secret")
+		_, err = resolveSecretRefs(context.TODO(), c, "default", map[string]interface{}{"password": ref("es-auth", "token")})
+		Expect(err).To(MatchError(ContainSubstring("password: secret es-auth has no key token")))
+		_, err = resolveSecretRefs(context.TODO(), c, "default", map[string]interface{}{"password": ref("kafka-auth", "password")})
+		Expect(err).To(MatchError(ContainSubstring("kafka-auth")))
+	})
+
+	It("should index the Secrets referred to by connectors and templates", func() {
+		connector := mock.CreateDefaultConnector("default")
+		connector.Spec.GlobalPatch = []byte(`{"password": {"valueFrom": {"secretKeyRef": {"name": "es-auth", "key": "password"}}}}`)
+		connector.Spec.Patches = []byte(`{"stream01": {"hosts": [{"valueFrom": {"secretKeyRef": {"name": "es-hosts", "key": "hosts"}}}]}}`)
+		Expect(connectorSecretNames(&connector)).To(Equal([]string{"es-auth", "es-hosts"}))
+
+		template := mock.CreateDefaultConnectorTemplate()
+		template.Spec.Config = `{"auth": {"valueFrom": {"secretKeyRef": {"name": "es-auth", "key": "auth"}}}}`
+		Expect(templateSecretNames(&template)).To(Equal([]string{"es-auth"}))
+
+		template.Spec.Config = `not json`
+		Expect(templateSecretNames(&template)).To(BeEmpty())
+	})
+
+	It("should report the ready, progressing and failed streams", func() {
+		connector := mock.CreateDefaultConnector("default")
+		connector.Generation = 2
+		replicas := int32(1)
+		rolledOut := &appsv1.Deployment{
+			Spec: appsv1.DeploymentSpec{Replicas: &replicas},
+			Status: appsv1.DeploymentStatus{
+				Replicas: 1, UpdatedReplicas: 1, ReadyReplicas: 1, AvailableReplicas: 1,
+			},
+		}
+		rollingOut := &appsv1.Deployment{
+			Spec:   appsv1.DeploymentSpec{Replicas: &replicas},
+			Status: appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 1, ReadyReplicas: 1, AvailableReplicas: 1},
+		}
+
+		status := newConnectorStatus(&connector, []connectorStream{
+			{status: v1beta1.ConnectorStreamStatus{Stream: "a", ReadyReplicas: 1}, deployment: rolledOut},
+			{status: v1beta1.ConnectorStreamStatus{Stream: "b", ReadyReplicas: 1}, deployment: rollingOut},
+			{status: v1beta1.ConnectorStreamStatus{Stream: "c", Error: "forbidden"}},
+			{status: v1beta1.ConnectorStreamStatus{Stream: "d", Error: "hosts in body is required"}, invalidConfig: true},
+		}, nil)
+
+		Expect(status.ObservedGeneration).To(BeEquivalentTo(2))
+		Expect(status.Streams).To(HaveLen(4))
+		Expect(status.ReadyStreams).To(Equal("1/4"))
+		Expect(status.Conditions).To(ConsistOf(
+			And(HaveField("Type", hapi.Ready), HaveField("Status", metav1.ConditionFalse),
+				HaveField("Message", ContainSubstring("b, c, d"))),
+			And(HaveField("Type", v1beta1.ConnectorProgressing), HaveField("Status", metav1.ConditionTrue),
+				HaveField("Message", HaveSuffix(": b"))),
+			And(HaveField("Type", v1beta1.ConnectorDegraded), HaveField("Status", metav1.ConditionTrue),
+				HaveField("Message", HaveSuffix(": c, d"))),
+			And(HaveField("Type", v1beta1.ConnectorConfigValid), HaveField("Status", metav1.ConditionFalse),
+				HaveField("Message", HaveSuffix(": d"))),
+		))
+
+		By("becoming ready once all streams are rolled out")
+		connector.Status = status
+		status = newConnectorStatus(&connector, []connectorStream{
+			{status: v1beta1.ConnectorStreamStatus{Stream: "a", ReadyReplicas: 1}, deployment: rolledOut},
+		}, nil)
+		Expect(status.ReadyStreams).To(Equal("1/1"))
+		Expect(meta.IsStatusConditionTrue(status.Conditions, hapi.Ready)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(status.Conditions, v1beta1.ConnectorDegraded)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(status.Conditions, v1beta1.ConnectorConfigValid)).To(BeTrue())
+
+		By("keeping the streams if the config is invalid")
+		connector.Status = status
+		status = newConnectorStatus(&connector, nil, fmt.Errorf("template not found"))
+		Expect(status.Streams).To(Equal(connector.Status.Streams))
+		Expect(meta.FindStatusCondition(status.Conditions, v1beta1.ConnectorDegraded).Reason).
+			To(Equal(v1beta1.ReasonConfigInvalid))
+	})
+
+	It("should validate the config of templates", func() {
+		scheme := runtime.NewScheme()
+		Expect(v1beta1.AddToScheme(scheme)).To(Succeed())
+		c := clientfake.NewClientBuilder().WithScheme(scheme).Build()
+		validate := func(template *v1beta1.ConnectorTemplate) metav1.Condition {
+			condition, err := validateTemplateConfig(context.TODO(), c, template)
+			Expect(err).To(Succeed())
+			return condition
+		}
+
+		template := mock.CreateDefaultConnectorTemplate()
+		condition := validate(&template)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+
+		By("not requiring the fields supplied by the patches")
+		template.Spec.Config = `{"scheme": "https"}`
+		Expect(validate(&template).Status).To(Equal(metav1.ConditionTrue))
+
+		template.Spec.Config = `{"scheme": "ftp"}`
+		condition = validate(&template)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(v1beta1.ReasonConfigInvalid))
+		Expect(condition.Message).To(ContainSubstring("scheme in body should be one of [http https]"))
+
+		By("rejecting the types without a ConnectorClass")
+		template.Spec.Type = "sink-unknown"
+		condition = validate(&template)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring(`unknown connector type "sink-unknown"`))
+	})
 })
@@ -0,0 +1,105 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"github.com/hstreamdb/hstream-operator/api/v1beta1"
+	"github.com/hstreamdb/hstream-operator/internal"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// errNoHServerEndpoint is returned for the connectors which set neither
+// hserverEndpoint nor hstreamDBRef, e.g. created before it was validated.
+var errNoHServerEndpoint = errors.New("one of spec.hserverEndpoint and spec.hstreamDBRef must be set")
+
+// connectorHStreamDBKey returns the namespaced name of the HStreamDB referred
+// to by the connector, or nil if the connector uses a raw endpoint.
+func connectorHStreamDBKey(connector *v1beta1.Connector) *types.NamespacedName {
+	ref := connector.Spec.HStreamDBRef
+	if ref == nil {
+		return nil
+	}
+	key := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
+	if key.Namespace == "" {
+		key.Namespace = connector.Namespace
+	}
+	return &key
+}
+
+// connectorPending describes why the connector pods cannot be created yet.
+type connectorPending struct {
+	reason  string
+	message string
+}
+
+// resolveHServerURL returns the service URL of HServer that the connector
+// connects to. If the referred HStreamDB is missing or not ready, the connector
+// is pending.
+func (r *ConnectorReconciler) resolveHServerURL(ctx context.Context, connector *v1beta1.Connector) (string, *connectorPending, error) {
+	key := connectorHStreamDBKey(connector)
+	if key == nil {
+		if connector.Spec.HServerEndpoint == "" {
+			return "", nil, errNoHServerEndpoint
+		}
+		return "hstream://" + connector.Spec.HServerEndpoint, nil, nil
+	}
+
+	hdb, err := getHStreamDB(ctx, r.Client, key.Namespace, connector.Spec.HStreamDBRef.HStreamDBReference)
+	if err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return "", nil, err
+		}
+		return "", &connectorPending{
+			reason:  hapi.ReasonHStreamDBNotFound,
+			message: fmt.Sprintf("HStreamDB %s not found", key),
+		}, nil
+	}
+	if !hdb.DeletionTimestamp.IsZero() || !hdb.IsConditionTrue(hapi.Ready) {
+		return "", &connectorPending{
+			reason:  hapi.ReasonHStreamDBNotReady,
+			message: fmt.Sprintf("HStreamDB %s is not ready", key),
+		}, nil
+	}
+	return hserverServiceURL(ctx, r.Client, hdb), nil, nil
+}
+
+// hserverServiceURL returns the URL of the internal HServer service, whose
+// scheme is hstreams if HServer enables TLS.
+func hserverServiceURL(ctx context.Context, c client.Client, hdb *hapi.HStreamDB) string {
+	scheme := "hstream"
+	if isHServerTLSEnabled(hdb) {
+		scheme = "hstreams"
+	}
+	service := internal.GetHeadlessService(hdb, hapi.ComponentTypeHServer)
+	return fmt.Sprintf("%s://%s.%s:%d", scheme, service.Name, hdb.Namespace, findHServerPort(ctx, c, hdb))
+}
+
+func isHServerTLSEnabled(hdb *hapi.HStreamDB) bool {
+	flags := internal.FlagSet{}
+	if err := flags.Parse(hdb.Spec.HServer.Container.Args); err != nil {
+		return false
+	}
+	value, ok := flags.Flags()["--enable-tls"]
+	return ok && value != "false"
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hstreamdb/hstream-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// secretKeyRefOf returns the Secret key referred to by a config value of the
+// form {"valueFrom": {"secretKeyRef": {"name": "...", "key": "..."}}}.
+func secretKeyRefOf(value interface{}) (*corev1.SecretKeySelector, bool) {
+	object, ok := value.(map[string]interface{})
+	if !ok || len(object) != 1 {
+		return nil, false
+	}
+	valueFrom, ok := object["valueFrom"].(map[string]interface{})
+	if !ok || len(valueFrom) != 1 {
+		return nil, false
+	}
+	ref, ok := valueFrom["secretKeyRef"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	name, _ := ref["name"].(string)
+	key, _ := ref["key"].(string)
+	optional, _ := ref["optional"].(bool)
+	return &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: name},
+		Key:                  key,
+		Optional:             &optional,
+	}, true
+}
+
+// resolveSecretRefs returns a copy of the config value in which the secretKeyRef
+// placeholders are replaced by the values of the Secret keys.
+func resolveSecretRefs(ctx context.Context, c client.Reader, namespace string, value interface{}) (interface{}, error) {
+	if ref, ok := secretKeyRefOf(value); ok {
+		return resolveSecretKeyRef(ctx, c, namespace, ref)
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		resolved := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			r, err := resolveSecretRefs(ctx, c, namespace, v)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+			resolved[k] = r
+		}
+		return resolved, nil
+	case []interface{}:
+		resolved := make([]interface{}, 0, len(value))
+		for i, v := range value {
+			r, err := resolveSecretRefs(ctx, c, namespace, v)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			resolved = append(resolved, r)
+		}
+		return resolved, nil
+	default:
+		return value, nil
+	}
+}
+
+func resolveSecretKeyRef(ctx context.Context, c client.Reader, namespace string, ref *corev1.SecretKeySelector) (interface{}, error) {
+	if ref.Name == "" || ref.Key == "" {
+		return nil, fmt.Errorf("secretKeyRef requires both name and key")
+	}
+
+	var secret corev1.Secret
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, &secret); err != nil {
+		if client.IgnoreNotFound(err) == nil && *ref.Optional {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("fail to get secret %s: %w", ref.Name, err)
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		if *ref.Optional {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("secret %s has no key %s", ref.Name, ref.Key)
+	}
+	return string(value), nil
+}
+
+// referencedSecrets adds the names of the Secrets referred to by the config
+// value into the set.
+func referencedSecrets(value interface{}, names map[string]bool) {
+	if ref, ok := secretKeyRefOf(value); ok {
+		names[ref.Name] = true
+		return
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for _, v := range value {
+			referencedSecrets(v, names)
+		}
+	case []interface{}:
+		for _, v := range value {
+			referencedSecrets(v, names)
+		}
+	}
+}
+
+const (
+	// connectorSecretsField indexes the Connectors by the Secrets referred to
+	// by their patches.
+	connectorSecretsField = ".spec.patches.secrets"
+	// templateSecretsField indexes the ConnectorTemplates by the Secrets
+	// referred to by their config.
+	templateSecretsField = ".spec.config.secrets"
+)
+
+// secretNames returns the names of the Secrets referred to by the JSON
+// documents, the invalid ones are skipped.
+func secretNames(docs ...[]byte) []string {
+	names := map[string]bool{}
+	for _, doc := range docs {
+		if doc == nil {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(doc, &value); err == nil {
+			referencedSecrets(value, names)
+		}
+	}
+
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// connectorSecretNames returns the names of the Secrets referred to by the
+// patches of the connector.
+func connectorSecretNames(connector *v1beta1.Connector) []string {
+	return secretNames(connector.Spec.GlobalPatch, connector.Spec.Patches)
+}
+
+// templateSecretNames returns the names of the Secrets referred to by the
+// config of the template.
+func templateSecretNames(template *v1beta1.ConnectorTemplate) []string {
+	return secretNames([]byte(template.Spec.Config))
+}
+
+// requestsForSecret maps a Secret to the Connectors referring to it, either by
+// their patches or by the config of their template, so that the connector pods
+// are rolled once a credential changes.
+func (r *ConnectorReconciler) requestsForSecret(obj client.Object) []ctrl.Request {
+	ctx := context.Background()
+	namespace := client.InNamespace(obj.GetNamespace())
+
+	var connectors v1beta1.ConnectorList
+	if err := r.List(ctx, &connectors, namespace,
+		client.MatchingFields{connectorSecretsField: obj.GetName()}); err != nil {
+		log.Error(err, "fail to list the Connectors of Secret", "Secret", obj.GetName())
+		return nil
+	}
+
+	var templates v1beta1.ConnectorTemplateList
+	if err := r.List(ctx, &templates, namespace,
+		client.MatchingFields{templateSecretsField: obj.GetName()}); err != nil {
+		log.Error(err, "fail to list the ConnectorTemplates of Secret", "Secret", obj.GetName())
+		return nil
+	}
+	for i := range templates.Items {
+		var users v1beta1.ConnectorList
+		if err := r.List(ctx, &users, namespace,
+			client.MatchingFields{connectorTemplateNameField: templates.Items[i].Name}); err != nil {
+			log.Error(err, "fail to list the Connectors of ConnectorTemplate", "ConnectorTemplate", templates.Items[i].Name)
+			return nil
+		}
+		connectors.Items = append(connectors.Items, users.Items...)
+	}
+
+	seen := make(map[types.NamespacedName]bool, len(connectors.Items))
+	var requests []ctrl.Request
+	for i := range connectors.Items {
+		key := client.ObjectKeyFromObject(&connectors.Items[i])
+		if !seen[key] {
+			seen[key] = true
+			requests = append(requests, ctrl.Request{NamespacedName: key})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"github.com/hstreamdb/hstream-operator/api/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// connectorStream is a stream served by a connector, as observed in the cluster.
+type connectorStream struct {
+	status v1beta1.ConnectorStreamStatus
+	// deployment is nil if the deployment failed to be applied.
+	deployment *appsv1.Deployment
+	// invalidConfig is set if the config rendered for the stream does not
+	// match the schema of the connector type.
+	invalidConfig bool
+	// ignoredStorageFields are the immutable fields of the storage which differ
+	// from the data PVC of the stream.
+	ignoredStorageFields []string
+}
+
+// isRolledOut reports whether all the replicas of the deployment are updated
+// and available.
+func isRolledOut(deployment *appsv1.Deployment) bool {
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == desired &&
+		deployment.Status.Replicas == desired &&
+		deployment.Status.AvailableReplicas == desired
+}
+
+// newPendingConnectorStatus returns the status of the connector whose pods
+// cannot be created yet, keeping the streams last observed.
+func newPendingConnectorStatus(connector *v1beta1.Connector, pending *connectorPending) v1beta1.ConnectorStatus {
+	status := v1beta1.ConnectorStatus{
+		ObservedGeneration: connector.Generation,
+		Streams:            connector.Status.Streams,
+		ReadyStreams:       connector.Status.ReadyStreams,
+		Conditions:         connector.Status.Conditions,
+	}
+	for _, conditionType := range []string{hapi.Ready, v1beta1.ConnectorProgressing} {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               conditionType,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: connector.Generation,
+			Reason:             pending.reason,
+			Message:            pending.message,
+		})
+	}
+	return status
+}
+
+// newConnectorStatus returns the status of the connector describing the
+// streams observed, or configErr if the config of the connector is invalid.
+func newConnectorStatus(connector *v1beta1.Connector, streams []connectorStream, configErr error) v1beta1.ConnectorStatus {
+	status := v1beta1.ConnectorStatus{
+		ObservedGeneration: connector.Generation,
+		Conditions:         connector.Status.Conditions,
+	}
+	setCondition := func(conditionType string, conditionStatus metav1.ConditionStatus, reason, message string) {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               conditionType,
+			Status:             conditionStatus,
+			ObservedGeneration: connector.Generation,
+			Reason:             reason,
+			Message:            message,
+		})
+	}
+
+	if configErr != nil {
+		// Keep the streams last observed, since none of them is reconciled.
+		status.Streams = connector.Status.Streams
+		status.ReadyStreams = connector.Status.ReadyStreams
+		setCondition(hapi.Ready, metav1.ConditionFalse, v1beta1.ReasonConfigInvalid, configErr.Error())
+		setCondition(v1beta1.ConnectorProgressing, metav1.ConditionFalse, v1beta1.ReasonConfigInvalid, configErr.Error())
+		setCondition(v1beta1.ConnectorDegraded, metav1.ConditionTrue, v1beta1.ReasonConfigInvalid, configErr.Error())
+		setCondition(v1beta1.ConnectorConfigValid, metav1.ConditionFalse, v1beta1.ReasonConfigInvalid, configErr.Error())
+		return status
+	}
+
+	var ready int
+	var notReady, rollingOut, failed, invalid, storageDiffers []string
+	for _, stream := range streams {
+		status.Streams = append(status.Streams, stream.status)
+		if stream.invalidConfig {
+			invalid = append(invalid, stream.status.Stream)
+		}
+		if len(stream.ignoredStorageFields) > 0 {
+			storageDiffers = append(storageDiffers,
+				fmt.Sprintf("%s (%s)", stream.status.Stream, strings.Join(stream.ignoredStorageFields, ", ")))
+		}
+		switch {
+		case stream.status.Error != "":
+			failed = append(failed, stream.status.Stream)
+			notReady = append(notReady, stream.status.Stream)
+		case stream.status.ReadyReplicas > 0 && isRolledOut(stream.deployment):
+			ready++
+		default:
+			rollingOut = append(rollingOut, stream.status.Stream)
+			notReady = append(notReady, stream.status.Stream)
+		}
+	}
+	status.ReadyStreams = fmt.Sprintf("%d/%d", ready, len(streams))
+
+	if len(notReady) == 0 {
+		setCondition(hapi.Ready, metav1.ConditionTrue, v1beta1.ReasonAllStreamsReady,
+			fmt.Sprintf("Connectors of %d streams are ready", ready))
+	} else {
+		setCondition(hapi.Ready, metav1.ConditionFalse, v1beta1.ReasonStreamsNotReady,
+			"Connectors of streams are not ready: "+strings.Join(notReady, ", "))
+	}
+
+	if len(rollingOut) == 0 {
+		setCondition(v1beta1.ConnectorProgressing, metav1.ConditionFalse, v1beta1.ReasonRolledOut,
+			"Connectors of all streams are rolled out")
+	} else {
+		setCondition(v1beta1.ConnectorProgressing, metav1.ConditionTrue, v1beta1.ReasonRollingOut,
+			"Connectors of streams are rolling out: "+strings.Join(rollingOut, ", "))
+	}
+
+	if len(failed) == 0 {
+		setCondition(v1beta1.ConnectorDegraded, metav1.ConditionFalse, v1beta1.ReasonReconcileSucceed,
+			"Connectors of all streams are reconciled")
+	} else {
+		setCondition(v1beta1.ConnectorDegraded, metav1.ConditionTrue, v1beta1.ReasonStreamsFailed,
+			"Connectors of streams failed to be reconciled: "+strings.Join(failed, ", "))
+	}
+
+	if len(invalid) == 0 {
+		setCondition(v1beta1.ConnectorConfigValid, metav1.ConditionTrue, v1beta1.ReasonConfigValidated,
+			"Configs of all streams are valid")
+	} else {
+		setCondition(v1beta1.ConnectorConfigValid, metav1.ConditionFalse, v1beta1.ReasonConfigInvalid,
+			"Configs of streams are invalid: "+strings.Join(invalid, ", "))
+	}
+
+	switch {
+	case connector.Spec.Storage == nil:
+		meta.RemoveStatusCondition(&status.Conditions, v1beta1.ConnectorStorageSynced)
+	case len(storageDiffers) == 0:
+		setCondition(v1beta1.ConnectorStorageSynced, metav1.ConditionTrue, v1beta1.ReasonStorageSynced,
+			"Data volumes of all streams match the storage")
+	default:
+		setCondition(v1beta1.ConnectorStorageSynced, metav1.ConditionFalse, v1beta1.ReasonStorageImmutable,
+			"Immutable fields of the storage differ from the data volumes of streams: "+strings.Join(storageDiffers, "; "))
+	}
+	return status
+}
@@ -18,18 +18,30 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"sort"
 
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"github.com/hstreamdb/hstream-operator/internal"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/hstreamdb/hstream-operator/api/v1beta1"
+	"github.com/hstreamdb/hstream-operator/pkg/connectorgen"
 )
 
 // ConnectorTemplateReconciler reconciles a ConnectorTemplate object
@@ -42,6 +54,7 @@ type ConnectorTemplateReconciler struct {
 //+kubebuilder:rbac:groups=apps.hstream.io,resources=connectortemplates/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=apps.hstream.io,resources=connectortemplates/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.hstream.io,resources=connectorclasses,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -64,54 +77,177 @@ func (r *ConnectorTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return ctrl.Result{}, nil
 	}
 
-	configMapName := v1beta1.GenConnectorConfigMapName(req.Name, true)
-	configMapNamespacedName := types.NamespacedName{
-		Namespace: req.Namespace,
-		Name:      configMapName,
+	configMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   req.Namespace,
+			Name:        v1beta1.GenConnectorConfigMapName(req.Name, true),
+			Annotations: map[string]string{},
+		},
+		Data: map[string]string{
+			"config": connectorTemplate.Spec.Config,
+		},
 	}
+	configMap.Annotations[hapi.LastSpecKey] = internal.GetObjectHash(configMap.Data)
 
-	var configMap corev1.ConfigMap
-	if err := r.Get(ctx, configMapNamespacedName, &configMap); err != nil {
-		if !k8sErrors.IsNotFound(err) {
-			return ctrl.Result{}, err
-		}
+	if err := r.applyConfigMap(ctx, &connectorTemplate, &configMap); err != nil {
+		log.Error(err, "fail to apply ConfigMap for ConnectorTemplate",
+			"ConnectorTemplate", connectorTemplate.Name,
+			"ConfigMap", configMap.Name,
+		)
 
-		configMap := corev1.ConfigMap{
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace: req.Namespace,
-				Name:      configMapName,
-			},
-			Data: map[string]string{
-				"config": connectorTemplate.Spec.Config,
-			},
-		}
+		return ctrl.Result{}, err
+	}
+
+	connectors, err := r.listConnectors(ctx, &connectorTemplate)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	condition, err := validateTemplateConfig(ctx, r.Client, &connectorTemplate)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
 
-		if err := controllerutil.SetControllerReference(&connectorTemplate, &configMap, r.Scheme); err != nil {
-			return ctrl.Result{}, err
+	status := v1beta1.ConnectorTemplateStatus{
+		ObservedGeneration: connectorTemplate.Generation,
+		Connectors:         connectors,
+		Conditions:         append([]metav1.Condition(nil), connectorTemplate.Status.Conditions...),
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+	if equality.Semantic.DeepEqual(connectorTemplate.Status, status) {
+		return ctrl.Result{}, nil
+	}
+	connectorTemplate.Status = status
+	return ctrl.Result{}, r.Status().Update(ctx, &connectorTemplate)
+}
+
+// validateTemplateConfig returns the ConfigValid condition of the template. The
+// fields required by the connector type are not checked, since they may be
+// supplied by the patches of the connectors.
+func validateTemplateConfig(ctx context.Context, c client.Reader, connectorTemplate *v1beta1.ConnectorTemplate) (metav1.Condition, error) {
+	condition := metav1.Condition{
+		Type:               v1beta1.ConnectorConfigValid,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: connectorTemplate.Generation,
+		Reason:             v1beta1.ReasonConfigInvalid,
+	}
+
+	class, err := connectorgen.ResolveConnectorClass(ctx, c, connectorTemplate.Spec.Type)
+	if err != nil {
+		if !errors.Is(err, connectorgen.ErrUnknownConnectorType) {
+			return condition, err
 		}
+		condition.Message = err.Error()
+		return condition, nil
+	}
+	schema, err := connectorgen.ConfigSchema(class)
+	if err != nil {
+		condition.Message = err.Error()
+		return condition, nil
+	}
 
-		// Create ConfigMap to store connector config.
-		err = r.Create(ctx, &configMap)
+	var config map[string]interface{}
+	if err = json.Unmarshal([]byte(connectorTemplate.Spec.Config), &config); err != nil {
+		condition.Message = "config is not a JSON object: " + err.Error()
+		return condition, nil
+	}
+	if err = connectorgen.ValidatePartialConfig(schema, config); err != nil {
+		condition.Message = err.Error()
+		return condition, nil
+	}
 
-		if err != nil {
-			log.Error(err, "fail to create ConfigMap for ConnectorTemplate",
-				"ConnectorTemplate", connectorTemplate.Name,
-				"ConfigMap", configMapName,
-			)
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = v1beta1.ReasonConfigValidated
+	condition.Message = "Config is valid"
+	return condition, nil
+}
 
-			return ctrl.Result{}, err
+// applyConfigMap creates the ConfigMap storing the template config, or updates
+// it once its hash changes.
+func (r *ConnectorTemplateReconciler) applyConfigMap(ctx context.Context, connectorTemplate *v1beta1.ConnectorTemplate, configMap *corev1.ConfigMap) error {
+	existing := &corev1.ConfigMap{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(configMap), existing); err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return err
+		}
+		if err = controllerutil.SetControllerReference(connectorTemplate, configMap, r.Scheme); err != nil {
+			return err
 		}
+		return r.Create(ctx, configMap)
+	}
+	if !isHashChanged(&existing.ObjectMeta, &configMap.ObjectMeta) {
+		return nil
 	}
 
-	// TODO: handle update logic.
+	log.Info("Update ConnectorTemplate ConfigMap", "ConnectorTemplate", connectorTemplate.Name, "ConfigMap", configMap.Name)
+	if existing.Annotations == nil {
+		existing.Annotations = map[string]string{}
+	}
+	existing.Annotations[hapi.LastSpecKey] = configMap.Annotations[hapi.LastSpecKey]
+	existing.Data = configMap.Data
+	return r.Update(ctx, existing)
+}
+
+// listConnectors returns the sorted names of the Connectors using the template.
+func (r *ConnectorTemplateReconciler) listConnectors(ctx context.Context, connectorTemplate *v1beta1.ConnectorTemplate) ([]string, error) {
+	var connectors v1beta1.ConnectorList
+	if err := r.List(ctx, &connectors, client.InNamespace(connectorTemplate.Namespace)); err != nil {
+		return nil, err
+	}
 
-	return ctrl.Result{}, nil
+	var names []string
+	for _, connector := range connectors.Items {
+		if connector.Spec.TemplateName == connectorTemplate.Name {
+			names = append(names, connector.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
 }
 
+// templateTypeField indexes the ConnectorTemplates by their type.
+const templateTypeField = ".spec.type"
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ConnectorTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1beta1.ConnectorTemplate{}, templateTypeField,
+		func(obj client.Object) []string {
+			return []string{string(obj.(*v1beta1.ConnectorTemplate).Spec.Type)}
+		}); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1beta1.ConnectorTemplate{}).
 		Owns(&corev1.ConfigMap{}).
+		// Keep the consumers in status up to date.
+		Watches(&source.Kind{Type: &v1beta1.Connector{}},
+			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
+				return []ctrl.Request{{NamespacedName: types.NamespacedName{
+					Namespace: obj.GetNamespace(),
+					Name:      obj.(*v1beta1.Connector).Spec.TemplateName,
+				}}}
+			})).
+		// Revalidate the templates once the class of their type changes.
+		Watches(&source.Kind{Type: &v1beta1.ConnectorClass{}},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForConnectorClass),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Complete(r)
 }
+
+// requestsForConnectorClass maps a ConnectorClass to the ConnectorTemplates of
+// its type from any namespace.
+func (r *ConnectorTemplateReconciler) requestsForConnectorClass(obj client.Object) []ctrl.Request {
+	var templates v1beta1.ConnectorTemplateList
+	if err := r.List(context.Background(), &templates,
+		client.MatchingFields{templateTypeField: obj.GetName()}); err != nil {
+		log.Error(err, "fail to list the ConnectorTemplates of ConnectorClass", "ConnectorClass", obj.GetName())
+		return nil
+	}
+
+	requests := make([]ctrl.Request, 0, len(templates.Items))
+	for i := range templates.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&templates.Items[i])})
+	}
+	return requests
+}
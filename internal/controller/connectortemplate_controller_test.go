@@ -26,9 +26,10 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-var _ = Describe("controller/connectortemplate", func() {
+var _ = Describe("controller/connectortemplate", Ordered, func() {
 	connectorTpl := mock.CreateDefaultConnectorTemplate()
 	connectorTpl.Namespace = "connector-template-test"
 
@@ -65,4 +66,28 @@ var _ = Describe("controller/connectortemplate", func() {
 		By("check if the owner reference of the configmap is set")
 		Expect(configMap.OwnerReferences).To(ContainElement(expectedOwnerReference))
 	})
+
+	It("should update the configmap and list the connectors using the template", func() {
+		connector := mock.CreateDefaultConnector(connectorTpl.Namespace)
+		Expect(k8sClient.Create(context.TODO(), &connector)).Should(Succeed())
+
+		By("listing the connector in status")
+		Eventually(func() []string {
+			_ = k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(&connectorTpl), &connectorTpl)
+			return connectorTpl.Status.Connectors
+		}).Should(Equal([]string{connector.Name}))
+
+		By("updating the template")
+		connectorTpl.Spec.Config = `{"hosts": "elasticsearch:9200"}`
+		Expect(k8sClient.Update(context.TODO(), &connectorTpl)).Should(Succeed())
+
+		var configMap corev1.ConfigMap
+		Eventually(func() string {
+			_ = k8sClient.Get(context.TODO(), types.NamespacedName{
+				Name:      v1beta1.GenConnectorConfigMapName(connectorTpl.Name, true),
+				Namespace: connectorTpl.Namespace,
+			}, &configMap)
+			return configMap.Data["config"]
+		}).Should(Equal(connectorTpl.Spec.Config))
+	})
 })
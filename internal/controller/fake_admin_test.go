@@ -0,0 +1,199 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"github.com/hstreamdb/hstream-operator/internal/admin"
+)
+
+type fakeAdminClientProvider struct {
+	client admin.IAdminClient
+}
+
+func (p fakeAdminClientProvider) GetAdminClient(*hapi.HStreamDB) admin.IAdminClient {
+	return p.client
+}
+
+func (p fakeAdminClientProvider) Invalidate(string, string) {}
+
+// fakeServerAdminClient serves the server commands of hadmin from memory.
+type fakeServerAdminClient struct {
+	admin.IAdminClient
+	streams       map[string]admin.Stream
+	subscriptions map[string]admin.Subscription
+	queries       map[string]admin.Query
+	views         map[string]admin.View
+	serverNodes   map[int]string
+	commands      []string
+	err           error
+}
+
+func newFakeServerAdminClient() *fakeServerAdminClient {
+	return &fakeServerAdminClient{
+		streams:       map[string]admin.Stream{},
+		subscriptions: map[string]admin.Subscription{},
+		queries:       map[string]admin.Query{},
+		views:         map[string]admin.View{},
+		serverNodes:   map[int]string{},
+	}
+}
+
+// flags returns the values of the flags following the positional arguments.
+func flags(args []string) map[string]string {
+	values := map[string]string{}
+	for i, arg := range args {
+		if !strings.HasPrefix(arg, "--") {
+			continue
+		}
+		if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+			values[arg] = args[i+1]
+		} else {
+			values[arg] = "true"
+		}
+	}
+	return values
+}
+
+func atoi32(value string) int32 {
+	n, _ := strconv.Atoi(value)
+	return int32(n)
+}
+
+func (c *fakeServerAdminClient) CallServer(_ context.Context, args ...string) (string, error) {
+	c.commands = append(c.commands, strings.Join(args, " "))
+	if c.err != nil {
+		return "", c.err
+	}
+
+	if args[0] == "sql" {
+		return c.executeSQL(args[1])
+	}
+	if args[0] == "status" {
+		output := "| node_id | state | address |\n"
+		ids := make([]int, 0, len(c.serverNodes))
+		for id := range c.serverNodes {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+		for _, id := range ids {
+			output += fmt.Sprintf("| %d | %s | hserver-%d:6570 |\n", id, c.serverNodes[id], id)
+		}
+		return output, nil
+	}
+
+	switch strings.Join(args[:2], " ") {
+	case "stream list":
+		output := "| Stream Name | Replication Factor | Backlog Duration | Shard Count |\n"
+		for _, name := range sortedKeys(c.streams) {
+			s := c.streams[name]
+			output += fmt.Sprintf("| %s | %d | %d | %d |\n",
+				s.Name, s.ReplicationFactor, int64(s.BacklogDuration/time.Second), s.ShardCount)
+		}
+		return output, nil
+	case "stream create":
+		f := flags(args)
+		backlog, _ := strconv.Atoi(f["--backlog-duration"])
+		c.streams[args[2]] = admin.Stream{
+			Name:              args[2],
+			ReplicationFactor: atoi32(f["--replication-factor"]),
+			ShardCount:        atoi32(f["--shards"]),
+			BacklogDuration:   time.Duration(backlog) * time.Second,
+		}
+		return "", nil
+	case "stream delete":
+		delete(c.streams, args[2])
+		return "", nil
+	case "sub list":
+		output := "| Subscription ID | Stream Name | Ack Timeout | Max Unacked Records | Consumers | Backlog |\n"
+		for _, id := range sortedKeys(c.subscriptions) {
+			s := c.subscriptions[id]
+			output += fmt.Sprintf("| %s | %s | %d | %d | %d | %d |\n",
+				s.ID, s.StreamName, s.AckTimeoutSeconds, s.MaxUnackedRecords, s.Consumers, s.Backlog)
+		}
+		return output, nil
+	case "sub create":
+		f := flags(args)
+		c.subscriptions[args[2]] = admin.Subscription{
+			ID:                args[2],
+			StreamName:        f["--stream"],
+			AckTimeoutSeconds: atoi32(f["--ack-timeout"]),
+			MaxUnackedRecords: atoi32(f["--max-unacked-records"]),
+		}
+		return "", nil
+	case "sub delete":
+		if c.subscriptions[args[2]].Consumers > 0 && flags(args)["--force"] == "" {
+			return "", fmt.Errorf("subscription %s has active consumers", args[2])
+		}
+		delete(c.subscriptions, args[2])
+		return "", nil
+	case "query list":
+		output := "| Query ID | Status | Node ID | SQL |\n"
+		for _, id := range sortedKeys(c.queries) {
+			q := c.queries[id]
+			output += fmt.Sprintf("| %s | %s | %d | %s |\n", q.ID, q.Status, q.NodeID, q.SQL)
+		}
+		return output, nil
+	case "query terminate", "query resume", "query delete":
+		q, ok := c.queries[args[2]]
+		if !ok {
+			return "", fmt.Errorf("query %s not found", args[2])
+		}
+		switch args[1] {
+		case "terminate":
+			q.Status = "TASK_TERMINATED"
+		case "resume":
+			q.Status = "TASK_RUNNING"
+		case "delete":
+			delete(c.queries, q.ID)
+			return "", nil
+		}
+		c.queries[q.ID] = q
+		return "", nil
+	case "view list":
+		output := "| View Name | Query ID | Status | Node ID | SQL |\n"
+		for _, name := range sortedKeys(c.views) {
+			v := c.views[name]
+			q := c.queries[v.QueryID]
+			output += fmt.Sprintf("| %s | %s | %s | %d | %s |\n", v.Name, q.ID, q.Status, q.NodeID, v.SQL)
+		}
+		return output, nil
+	case "view delete":
+		delete(c.queries, c.views[args[2]].QueryID)
+		delete(c.views, args[2])
+		return "", nil
+	}
+	return "", fmt.Errorf("unknown command %v", args)
+}
+
+// executeSQL runs the query of a statement, and materializes it in a view
+// for CREATE VIEW.
+func (c *fakeServerAdminClient) executeSQL(sql string) (string, error) {
+	query := admin.Query{
+		ID:     fmt.Sprintf("cli_generated_%d", len(c.commands)),
+		Status: "TASK_RUNNING",
+		NodeID: 100,
+		SQL:    sql,
+	}
+	c.queries[query.ID] = query
+
+	if rest, ok := strings.CutPrefix(sql, "CREATE VIEW "); ok {
+		name, selectSQL, _ := strings.Cut(rest, " AS ")
+		c.views[name] = admin.View{Name: name, QueryID: query.ID, SQL: selectSQL}
+	}
+	return "", nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
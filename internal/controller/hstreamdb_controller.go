@@ -18,16 +18,18 @@ package controller
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
 	"github.com/hstreamdb/hstream-operator/internal/admin"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -36,6 +38,10 @@ import (
 
 var log = logf.Log.WithName("HStreamDB Controller")
 
+// healthCheckInterval defines how often a fully reconciled HStreamDB is
+// reconciled again to re-evaluate the health of its components.
+const healthCheckInterval = 30 * time.Second
+
 // HStreamDBReconciler reconciles a HStreamDB object
 type HStreamDBReconciler struct {
 	client.Client
@@ -78,51 +84,124 @@ func (r *HStreamDBReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	hdb := &hapi.HStreamDB{}
 	if err = r.Get(ctx, req.NamespacedName, hdb); err != nil {
 		if k8sErrors.IsNotFound(err) {
+			deleteClusterMetrics(req.Namespace, req.Name)
+			r.AdminClientProvider.Invalidate(req.Namespace, req.Name)
 			err = nil
 		}
 		// Error reading the object - requeue the request.
 		return
 	}
 
-	subReconcilers := []hdbSubReconciler{
-		LogDeviceConfigReconciler{},
-		addServices{},
-		addHMeta{},
-		updateHMetaStatus{},
-		addAdminServer{},
-		addHStore{},
-		bootstrapHStore{},
-		addHServer{},
-		bootstrapHServer{},
-		addGateway{},
-		addConsole{},
-		updateStatus{},
+	res, err = r.subReconcile(ctx, hdb, hdbSubReconcilerSteps())
+	if err == nil && res.IsZero() {
+		res.RequeueAfter = healthCheckInterval
+	}
+	return
+}
+
+// hdbSubReconcilerSteps returns the sub-reconcilers with their dependencies.
+// A step that is blocked only stops the steps depending on it.
+func hdbSubReconcilerSteps() []subReconcilerStep {
+	return []subReconcilerStep{
+		{name: "LogDeviceConfig", reconciler: LogDeviceConfigReconciler{}},
+		{name: "Services", reconciler: addServices{}},
+		{name: "HMeta", reconciler: addHMeta{}, component: hapi.ComponentTypeHMeta},
+		{name: "HMetaStatus", reconciler: updateHMetaStatus{}, dependsOn: []string{"HMeta"}, statusOnly: true},
+		{name: "AdminServer", reconciler: addAdminServer{}, dependsOn: []string{"LogDeviceConfig", "HMetaStatus"},
+			component: hapi.ComponentTypeAdminServer},
+		{name: "HStore", reconciler: addHStore{}, dependsOn: []string{"LogDeviceConfig", "HMetaStatus"},
+			component: hapi.ComponentTypeHStore},
+		{name: "BootstrapHStore", reconciler: bootstrapHStore{}, dependsOn: []string{"AdminServer", "HStore"},
+			component: hapi.ComponentTypeHStore},
+		{name: "HServer", reconciler: addHServer{}, dependsOn: []string{"Services", "BootstrapHStore"},
+			component: hapi.ComponentTypeHServer},
+		{name: "BootstrapHServer", reconciler: bootstrapHServer{}, dependsOn: []string{"HServer"},
+			component: hapi.ComponentTypeHServer},
+		{name: "Gateway", reconciler: addGateway{}, dependsOn: []string{"BootstrapHServer"},
+			component: hapi.ComponentTypeGateway},
+		{name: "Console", reconciler: addConsole{}, dependsOn: []string{"Services"},
+			component: hapi.ComponentTypeConsole},
+		{name: "Status", reconciler: updateStatus{}, final: true},
 	}
-	return r.subReconcile(ctx, hdb, subReconcilers)
 }
 
-func (r *HStreamDBReconciler) subReconcile(ctx context.Context, hdb *hapi.HStreamDB, subReconcilers []hdbSubReconciler) (
+// subReconcile runs the steps as a DAG, and then the final steps one by one.
+// Errors and requeues of all steps are gathered, so that one slow component
+// neither blocks unrelated components nor hides their errors.
+func (r *HStreamDBReconciler) subReconcile(ctx context.Context, hdb *hapi.HStreamDB, steps []subReconcilerStep) (
 	ctrl.Result, error) {
 
 	logger := log.WithValues("namespace", hdb.Namespace, "instance", hdb.Name)
 
-	delayedRequeue := false
-	for _, subReconciler := range subReconcilers {
-		logger.V(1).Info("Attempting to run sub-reconciler", "subReconciler", fmt.Sprintf("%T", subReconciler))
-		requeue := subReconciler.reconcile(ctx, r, hdb)
-		if requeue == nil {
+	if err := validateSteps(steps); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	r.updatePausedCondition(hdb)
+
+	results := r.runSteps(ctx, hdb, steps)
+	recordStepResults(hdb, results)
+	updateReconcileErrorCondition(hdb, results)
+
+	finalFailed := false
+	for _, step := range steps {
+		if !step.final {
 			continue
 		}
+		logger.V(1).Info("Attempting to run sub-reconciler", "subReconciler", step.name)
+		result := runStep(ctx, r, hdb, step)
+		if result.requeue != nil && result.requeue.isFailure() {
+			finalFailed = true
+		}
+		results = append(results, result)
+	}
+
+	// A failed final step, e.g. updating the status, may have returned
+	// before the status was written, so the ReconcileError condition which
+	// includes its failure is written here.
+	if finalFailed {
+		updateReconcileErrorCondition(hdb, results)
+		if err := r.Status().Update(ctx, hdb); err != nil {
+			logger.Error(err, "Failed to record the ReconcileError condition")
+		}
+	}
 
-		if requeue.delayedRequeue {
+	var errs []error
+	var requeueAfter time.Duration
+	delayedRequeue := false
+	for _, result := range results {
+		observeStep(result)
+		switch result.outcome {
+		case hapi.ReconcileStepSucceeded, hapi.ReconcileStepSkipped, hapi.ReconcileStepPaused:
+			continue
+		case hapi.ReconcileStepDelayed:
 			logger.V(1).Info("Delaying requeue for sub-reconciler",
-				"subReconciler", fmt.Sprintf("%T", subReconciler),
-				"message", requeue.message,
-				"error", requeue.curError)
+				"subReconciler", result.step.name,
+				"message", result.requeue.message,
+				"error", result.requeue.curError)
 			delayedRequeue = true
 			continue
 		}
-		return processRequeue(requeue, subReconciler, hdb, r.Recorder, logger)
+
+		res, err := processRequeue(result.requeue, result.step.name, hdb, r.Recorder, logger)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if res.RequeueAfter > 0 && (requeueAfter == 0 || res.RequeueAfter < requeueAfter) {
+			requeueAfter = res.RequeueAfter
+		}
+	}
+
+	if len(errs) > 0 {
+		return ctrl.Result{}, utilerrors.NewAggregate(errs)
+	}
+	if requeueAfter > 0 {
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+	for _, result := range results {
+		if result.outcome == hapi.ReconcileStepBlocked {
+			return ctrl.Result{}, nil
+		}
 	}
 
 	if delayedRequeue {
@@ -130,6 +209,11 @@ func (r *HStreamDBReconciler) subReconcile(ctx context.Context, hdb *hapi.HStrea
 		return ctrl.Result{RequeueAfter: time.Second}, nil
 	}
 
+	if hdb.Spec.Pause != nil {
+		logger.V(1).Info("Reconciliation is paused")
+		return ctrl.Result{}, nil
+	}
+
 	logger.Info("Reconciliation complete")
 	r.Recorder.Event(hdb, corev1.EventTypeNormal, "ReconciliationComplete", "")
 
@@ -138,13 +222,18 @@ func (r *HStreamDBReconciler) subReconcile(ctx context.Context, hdb *hapi.HStrea
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *HStreamDBReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.AdminClientProvider = instrumentedAdminClientProvider{r.AdminClientProvider}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&hapi.HStreamDB{}).
-		WithEventFilter(predicate.Funcs{
+		For(&hapi.HStreamDB{}, builder.WithPredicates(predicate.Funcs{
 			UpdateFunc: func(e event.UpdateEvent) bool {
 				// Ignore updates to CR status in which case metadata.Generation does not change
 				return e.ObjectNew.GetGeneration() != e.ObjectOld.GetGeneration()
 			},
-		}).
+		})).
+		// Watch the workloads of components, so that the change of their
+		// ready replicas is reflected in the conditions of HStreamDB.
+		Owns(&appsv1.StatefulSet{}).
+		Owns(&appsv1.Deployment{}).
 		Complete(r)
 }
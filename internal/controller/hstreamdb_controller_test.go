@@ -3,14 +3,19 @@ package controller
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"time"
 
 	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"github.com/hstreamdb/hstream-operator/internal/admin"
 	"github.com/hstreamdb/hstream-operator/mock"
+	"github.com/hstreamdb/hstream-operator/pkg/executor"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -55,14 +60,14 @@ var _ = Describe("BootstrapHServer", Label("k8s"), func() {
 var _ = Describe("HstreamdbController", func() {
 	var mockRec *mockReconcile
 	var hdb *hapi.HStreamDB
-	var subReconcilers []hdbSubReconciler
+	var subReconcilers []subReconcilerStep
 	ctx := context.TODO()
 
 	BeforeEach(func() {
 		hdb = mock.CreateDefaultCR()
 		mockRec = &mockReconcile{}
-		subReconcilers = []hdbSubReconciler{
-			mockRec,
+		subReconcilers = []subReconcilerStep{
+			{name: "Mock", reconciler: mockRec},
 		}
 	})
 
@@ -105,10 +110,224 @@ var _ = Describe("HstreamdbController", func() {
 	})
 })
 
+var _ = Describe("HstreamdbController/graph", func() {
+	var hdb *hapi.HStreamDB
+	ctx := context.TODO()
+
+	BeforeEach(func() {
+		hdb = mock.CreateDefaultCR()
+	})
+
+	It("should run independent steps while a step is blocked", func() {
+		blocked := &mockReconcile{rq: &requeue{message: "wait for HMeta", delay: 5 * time.Second}}
+		dependent := &mockReconcile{}
+		independent := &mockReconcile{}
+		final := &mockReconcile{}
+
+		res, err := clusterReconciler.subReconcile(ctx, hdb, []subReconcilerStep{
+			{name: "Blocked", reconciler: blocked},
+			{name: "Dependent", reconciler: dependent, dependsOn: []string{"Blocked"}},
+			{name: "Independent", reconciler: independent},
+			{name: "Final", reconciler: final, final: true},
+		})
+		Expect(err).To(Succeed())
+		Expect(res).To(Equal(ctrl.Result{RequeueAfter: 5 * time.Second}))
+
+		Expect(blocked.called.Load()).To(BeEquivalentTo(1))
+		Expect(dependent.called.Load()).To(BeEquivalentTo(0))
+		Expect(independent.called.Load()).To(BeEquivalentTo(1))
+		Expect(final.called.Load()).To(BeEquivalentTo(1))
+
+		Expect(hdb.Status.Reconciliation).To(Equal([]hapi.ReconcileStep{
+			{Name: "Blocked", Outcome: hapi.ReconcileStepBlocked, Message: "wait for HMeta"},
+			{Name: "Dependent", Outcome: hapi.ReconcileStepSkipped, Message: "dependency Blocked did not succeed"},
+			{Name: "Independent", Outcome: hapi.ReconcileStepSucceeded},
+		}))
+	})
+
+	It("should gather errors of all steps", func() {
+		res, err := clusterReconciler.subReconcile(ctx, hdb, []subReconcilerStep{
+			{name: "First", reconciler: &mockReconcile{rq: &requeue{curError: errors.New("first error")}}},
+			{name: "Second", reconciler: &mockReconcile{rq: &requeue{curError: errors.New("second error")}}},
+		})
+		Expect(res).To(Equal(ctrl.Result{}))
+		Expect(err).To(MatchError(ContainSubstring("first error")))
+		Expect(err).To(MatchError(ContainSubstring("second error")))
+	})
+
+	It("should merge status changes of steps", func() {
+		setter := &mockReconcile{condition: &metav1.Condition{
+			Type:   hapi.HStoreBootstrapped,
+			Status: metav1.ConditionTrue,
+			Reason: hapi.ReasonBootstrapped,
+		}}
+
+		_, err := clusterReconciler.subReconcile(ctx, hdb, []subReconcilerStep{
+			{name: "Setter", reconciler: setter},
+		})
+		Expect(err).To(Succeed())
+		Expect(hdb.IsConditionTrue(hapi.HStoreBootstrapped)).To(BeTrue())
+	})
+
+	It("should reject invalid graphs", func() {
+		_, err := clusterReconciler.subReconcile(ctx, hdb, []subReconcilerStep{
+			{name: "A", reconciler: &mockReconcile{}, dependsOn: []string{"B"}},
+			{name: "B", reconciler: &mockReconcile{}, dependsOn: []string{"A"}},
+		})
+		Expect(err).To(HaveOccurred())
+
+		_, err = clusterReconciler.subReconcile(ctx, hdb, []subReconcilerStep{
+			{name: "A", reconciler: &mockReconcile{}, dependsOn: []string{"Unknown"}},
+		})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should not run paused steps", func() {
+		hdb.Spec.Pause = &hapi.Pause{Components: []hapi.ComponentType{hapi.ComponentTypeHStore}}
+		hstore := &mockReconcile{}
+		dependent := &mockReconcile{}
+		status := &mockReconcile{}
+		final := &mockReconcile{}
+
+		_, err := clusterReconciler.subReconcile(ctx, hdb, []subReconcilerStep{
+			{name: "HStore", reconciler: hstore, component: hapi.ComponentTypeHStore},
+			{name: "HServer", reconciler: dependent, dependsOn: []string{"HStore"}, component: hapi.ComponentTypeHServer},
+			{name: "Status", reconciler: status, statusOnly: true, component: hapi.ComponentTypeHStore},
+			{name: "Final", reconciler: final, final: true},
+		})
+		Expect(err).To(Succeed())
+
+		Expect(hstore.called.Load()).To(BeEquivalentTo(0))
+		Expect(dependent.called.Load()).To(BeEquivalentTo(0))
+		Expect(status.called.Load()).To(BeEquivalentTo(1))
+		Expect(final.called.Load()).To(BeEquivalentTo(1))
+		Expect(hdb.Status.Reconciliation[0].Outcome).To(Equal(hapi.ReconcileStepPaused))
+		Expect(hdb.Status.Reconciliation[1].Outcome).To(Equal(hapi.ReconcileStepSkipped))
+
+		_, condition := hdb.GetCondition(hapi.Paused)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(Equal("Reconciliation of hstore is paused"))
+
+		hdb.Spec.Pause = &hapi.Pause{}
+		_, err = clusterReconciler.subReconcile(ctx, hdb, []subReconcilerStep{
+			{name: "HServer", reconciler: dependent, component: hapi.ComponentTypeHServer},
+		})
+		Expect(err).To(Succeed())
+		Expect(dependent.called.Load()).To(BeEquivalentTo(1))
+
+		hdb.Spec.Pause = nil
+		_, err = clusterReconciler.subReconcile(ctx, hdb, []subReconcilerStep{
+			{name: "HServer", reconciler: dependent, component: hapi.ComponentTypeHServer},
+		})
+		Expect(err).To(Succeed())
+		Expect(dependent.called.Load()).To(BeEquivalentTo(2))
+		Expect(hdb.IsConditionTrue(hapi.Paused)).To(BeFalse())
+	})
+
+	It("should report the status of a paused component", func() {
+		hdb.Spec.Pause = &hapi.Pause{Components: []hapi.ComponentType{hapi.ComponentTypeHMeta}}
+		hmeta := &mockReconcile{}
+		hmetaStatus := &mockReconcile{}
+		hstore := &mockReconcile{}
+
+		_, err := clusterReconciler.subReconcile(ctx, hdb, []subReconcilerStep{
+			{name: "HMeta", reconciler: hmeta, component: hapi.ComponentTypeHMeta},
+			{name: "HMetaStatus", reconciler: hmetaStatus, dependsOn: []string{"HMeta"}, statusOnly: true},
+			{name: "HStore", reconciler: hstore, dependsOn: []string{"HMetaStatus"}, component: hapi.ComponentTypeHStore},
+		})
+		Expect(err).To(Succeed())
+
+		Expect(hmeta.called.Load()).To(BeEquivalentTo(0))
+		Expect(hmetaStatus.called.Load()).To(BeEquivalentTo(1))
+		Expect(hstore.called.Load()).To(BeEquivalentTo(0))
+		Expect(hdb.Status.Reconciliation[0].Outcome).To(Equal(hapi.ReconcileStepPaused))
+		Expect(hdb.Status.Reconciliation[1].Outcome).To(Equal(hapi.ReconcileStepSucceeded))
+		Expect(hdb.Status.Reconciliation[2].Outcome).To(Equal(hapi.ReconcileStepSkipped))
+	})
+
+	It("should set ReconcileError condition from failed steps", func() {
+		_, err := clusterReconciler.subReconcile(ctx, hdb, []subReconcilerStep{
+			{name: "Bootstrap", reconciler: &mockReconcile{rq: &requeue{
+				message: "exec failed", delay: time.Second, reason: reasonBootstrapFailed,
+			}}},
+			{name: "Wait", reconciler: &mockReconcile{rq: &requeue{
+				message: "pods are not running", delay: time.Second, reason: reasonWaitingForPods,
+			}}},
+		})
+		Expect(err).To(Succeed())
+
+		_, condition := hdb.GetCondition(hapi.ReconcileError)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(reasonBootstrapFailed)))
+		Expect(condition.Message).To(Equal("Bootstrap: exec failed"))
+
+		_, err = clusterReconciler.subReconcile(ctx, hdb, []subReconcilerStep{
+			{name: "Bootstrap", reconciler: &mockReconcile{}},
+		})
+		Expect(err).To(Succeed())
+		Expect(hdb.IsConditionTrue(hapi.ReconcileError)).To(BeFalse())
+	})
+
+	It("should include failures of final steps in ReconcileError condition", func() {
+		_, err := clusterReconciler.subReconcile(ctx, hdb, []subReconcilerStep{
+			{name: "Bootstrap", reconciler: &mockReconcile{rq: &requeue{
+				message: "exec failed", delay: time.Second, reason: reasonBootstrapFailed,
+			}}},
+			{name: "Status", final: true, reconciler: &mockReconcile{rq: &requeue{
+				curError: errors.New("list pods failed"), reason: reasonStatusUpdateFailed,
+			}}},
+		})
+		Expect(err).To(HaveOccurred())
+
+		_, condition := hdb.GetCondition(hapi.ReconcileError)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(reasonBootstrapFailed)))
+		Expect(condition.Message).To(Equal("Bootstrap: exec failed; Status: list pods failed"))
+	})
+
+	It("should record warning events for failures only", func() {
+		recorder := record.NewFakeRecorder(10)
+		logger := log.WithValues("test", "requeue")
+
+		_, err := processRequeue(&requeue{message: "exec failed", reason: reasonBootstrapFailed},
+			"Bootstrap", hdb, recorder, logger)
+		Expect(err).To(Succeed())
+		Expect(<-recorder.Events).To(Equal("Warning BootstrapFailed exec failed"))
+
+		_, err = processRequeue(&requeue{message: "pods are not running", reason: reasonWaitingForPods},
+			"Bootstrap", hdb, recorder, logger)
+		Expect(err).To(Succeed())
+		Expect(<-recorder.Events).To(Equal("Normal WaitingForPods pods are not running"))
+
+		_, err = processRequeue(&requeue{curError: errors.New("boom")}, "Bootstrap", hdb, recorder, logger)
+		Expect(err).To(HaveOccurred())
+		Expect(<-recorder.Events).To(Equal("Warning ReconcileFailed boom"))
+	})
+
+	It("should tell admin commands which failed from the ones which could not be run", func() {
+		exitErr := &executor.ExitError{Command: "hadmin server init", Result: &executor.Result{ExitCode: 1}}
+		Expect(adminFailureReason(exitErr, reasonBootstrapFailed)).To(Equal(reasonBootstrapFailed))
+		Expect(adminFailureReason(admin.ErrNoReadyAdminPod, reasonBootstrapFailed)).To(Equal(reasonAdminExecFailed))
+	})
+
+	It("should declare a valid graph for HStreamDB", func() {
+		Expect(validateSteps(hdbSubReconcilerSteps())).To(Succeed())
+	})
+})
+
 type mockReconcile struct {
-	rq *requeue
+	rq        *requeue
+	condition *metav1.Condition
+	called    atomic.Int32
 }
 
-func (m mockReconcile) reconcile(_ context.Context, _ *HStreamDBReconciler, _ *hapi.HStreamDB) *requeue {
+func (m *mockReconcile) reconcile(_ context.Context, _ *HStreamDBReconciler, hdb *hapi.HStreamDB) *requeue {
+	m.called.Add(1)
+	if m.condition != nil {
+		hdb.SetCondition(*m.condition)
+	}
 	return m.rq
 }
@@ -0,0 +1,93 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// hstreamDBRefField indexes the resources referring to an HStreamDB, e.g.
+// Stream, by the namespaced name of the HStreamDB, see hstreamDBRefKey.
+const hstreamDBRefField = ".spec.hstreamDBRef"
+
+// hstreamDBRefKey returns the value of hstreamDBRefField for a reference to an
+// HStreamDB in the namespace.
+func hstreamDBRefKey(namespace string, ref hapi.HStreamDBReference) string {
+	return types.NamespacedName{Namespace: namespace, Name: ref.Name}.String()
+}
+
+// getHStreamDB returns the HStreamDB referred to by a resource in the namespace.
+func getHStreamDB(ctx context.Context, c client.Reader, namespace string, ref hapi.HStreamDBReference) (*hapi.HStreamDB, error) {
+	hdb := &hapi.HStreamDB{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, hdb); err != nil {
+		return nil, err
+	}
+	return hdb, nil
+}
+
+// isHStreamDBReady reports whether the admin commands can be sent to the HStreamDB.
+func isHStreamDBReady(hdb *hapi.HStreamDB) bool {
+	return hdb.DeletionTimestamp.IsZero() &&
+		hdb.IsConditionTrue(hapi.HServerReady) &&
+		hdb.IsConditionTrue(hapi.AdminServerReady)
+}
+
+// hstreamDBReadinessChanged filters the updates of HStreamDB which change
+// neither whether the admin commands can be sent nor its Ready condition, e.g.
+// the periodic status updates of the HStreamDB controller.
+var hstreamDBReadinessChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldHdb, okOld := e.ObjectOld.(*hapi.HStreamDB)
+		newHdb, okNew := e.ObjectNew.(*hapi.HStreamDB)
+		return !okOld || !okNew || isHStreamDBReady(oldHdb) != isHStreamDBReady(newHdb) ||
+			oldHdb.IsConditionTrue(hapi.Ready) != newHdb.IsConditionTrue(hapi.Ready)
+	},
+}
+
+// requestsForHStreamDB maps an HStreamDB to the resources of the list type
+// referring to it, so that they are reconciled once the HStreamDB changes.
+// The resources must be indexed by hstreamDBRefField.
+func requestsForHStreamDB(c client.Reader, list client.ObjectList) handler.MapFunc {
+	return func(obj client.Object) []ctrl.Request {
+		list := list.DeepCopyObject().(client.ObjectList)
+		if err := c.List(context.Background(), list,
+			client.MatchingFields{hstreamDBRefField: client.ObjectKeyFromObject(obj).String()},
+		); err != nil {
+			log.Error(err, "failed to list resources referring to HStreamDB",
+				"namespace", obj.GetNamespace(), "name", obj.GetName())
+			return nil
+		}
+
+		var requests []ctrl.Request
+		_ = meta.EachListItem(list, func(item runtime.Object) error {
+			o := item.(client.Object)
+			requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(o)})
+			return nil
+		})
+		return requests
+	}
+}
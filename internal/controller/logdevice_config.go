@@ -61,7 +61,7 @@ func (lc LogDeviceConfigReconciler) reconcile(ctx context.Context, r *HStreamDBR
 			logger.Error(err, "failed to create ConfigMap for LogDevice config",
 				"ConfigMap", logDeviceConfigMap.Name)
 
-			return &requeue{curError: err}
+			return &requeue{curError: err, reason: reasonApplyFailed}
 		}
 	}
 
@@ -90,7 +90,7 @@ func (lc LogDeviceConfigReconciler) reconcile(ctx context.Context, r *HStreamDBR
 			logger.Error(err, "failed to create ConfigMap for nShards config",
 				"ConfigMap", nShardsConfigMap.Name)
 
-			return &requeue{curError: err}
+			return &requeue{curError: err, reason: reasonApplyFailed}
 		}
 	}
 
@@ -0,0 +1,242 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"github.com/hstreamdb/hstream-operator/internal/admin"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const metricsNamespace = "hstream_operator"
+
+var (
+	reconcileStepDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "reconcile_step_duration_seconds",
+		Help:      "Duration of the sub-reconcilers of HStreamDB.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 10),
+	}, []string{"step"})
+
+	reconcileStepTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "reconcile_steps_total",
+		Help:      "Number of sub-reconciler runs of HStreamDB by outcome.",
+	}, []string{"step", "outcome"})
+
+	requeueTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "requeue_total",
+		Help:      "Number of requeues requested by the sub-reconcilers of HStreamDB by reason.",
+	}, []string{"step", "reason"})
+
+	adminCallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "admin_call_duration_seconds",
+		Help:      "Latency of the admin commands sent to HStreamDB clusters.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+	}, []string{"command"})
+
+	adminCallFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "admin_call_failures_total",
+		Help:      "Number of admin commands sent to HStreamDB clusters that failed.",
+	}, []string{"command"})
+
+	componentReady = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "component_ready",
+		Help:      "Whether a component of an HStreamDB cluster is ready (1) or not (0).",
+	}, []string{"namespace", "name", "component"})
+
+	hmetaReachableNodes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "hmeta_reachable_nodes",
+		Help:      "Number of reachable HMeta nodes of an HStreamDB cluster.",
+	}, []string{"namespace", "name"})
+
+	hmetaLeaderNodes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "hmeta_leader_nodes",
+		Help:      "Number of HMeta nodes of an HStreamDB cluster that consider themselves the leader.",
+	}, []string{"namespace", "name"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		reconcileStepDuration,
+		reconcileStepTotal,
+		requeueTotal,
+		adminCallDuration,
+		adminCallFailuresTotal,
+		componentReady,
+		hmetaReachableNodes,
+		hmetaLeaderNodes,
+	)
+}
+
+// readinessConditions maps the components to their readiness conditions.
+var readinessConditions = map[hapi.ComponentType]string{
+	hapi.ComponentTypeHMeta:       hapi.HMetaReady,
+	hapi.ComponentTypeAdminServer: hapi.AdminServerReady,
+	hapi.ComponentTypeHStore:      hapi.HStoreReady,
+	hapi.ComponentTypeHServer:     hapi.HServerReady,
+	hapi.ComponentTypeConsole:     hapi.ConsoleReady,
+	hapi.ComponentTypeGateway:     hapi.GatewayReady,
+}
+
+// observeStep records the outcome of a step, and its duration if it ran.
+func observeStep(result stepResult) {
+	reconcileStepTotal.WithLabelValues(result.step.name, string(result.outcome)).Inc()
+	if result.duration > 0 {
+		reconcileStepDuration.WithLabelValues(result.step.name).Observe(result.duration.Seconds())
+	}
+}
+
+// recordClusterMetrics exports the readiness of the components and the HMeta
+// nodes of the cluster as gauges.
+func recordClusterMetrics(hdb *hapi.HStreamDB) {
+	for component, condition := range readinessConditions {
+		if _, c := hdb.GetCondition(condition); c == nil {
+			componentReady.DeleteLabelValues(hdb.Namespace, hdb.Name, string(component))
+			continue
+		}
+		value := 0.0
+		if hdb.IsConditionTrue(condition) {
+			value = 1
+		}
+		componentReady.WithLabelValues(hdb.Namespace, hdb.Name, string(component)).Set(value)
+	}
+
+	reachable, leaders := 0, 0
+	for _, node := range hdb.Status.HMeta.Nodes {
+		if node.Reachable {
+			reachable++
+		}
+		if node.Leader {
+			leaders++
+		}
+	}
+	hmetaReachableNodes.WithLabelValues(hdb.Namespace, hdb.Name).Set(float64(reachable))
+	hmetaLeaderNodes.WithLabelValues(hdb.Namespace, hdb.Name).Set(float64(leaders))
+}
+
+// deleteClusterMetrics removes the gauges of a deleted cluster.
+func deleteClusterMetrics(namespace, name string) {
+	for component := range readinessConditions {
+		componentReady.DeleteLabelValues(namespace, name, string(component))
+	}
+	hmetaReachableNodes.DeleteLabelValues(namespace, name)
+	hmetaLeaderNodes.DeleteLabelValues(namespace, name)
+}
+
+// instrumentedAdminClientProvider wraps the admin clients so that the latency
+// and failures of the admin commands are recorded.
+type instrumentedAdminClientProvider struct {
+	admin.AdminClientProvider
+}
+
+func (p instrumentedAdminClientProvider) GetAdminClient(hdb *hapi.HStreamDB) admin.IAdminClient {
+	return instrumentedAdminClient{client: p.AdminClientProvider.GetAdminClient(hdb)}
+}
+
+type instrumentedAdminClient struct {
+	client admin.IAdminClient
+}
+
+func (c instrumentedAdminClient) CallServer(ctx context.Context, args ...string) (string, error) {
+	return observeAdminCall(adminCommand("server", args), func() (string, error) {
+		return c.client.CallServer(ctx, args...)
+	})
+}
+
+func (c instrumentedAdminClient) CallStore(ctx context.Context, args ...string) (string, error) {
+	return observeAdminCall(adminCommand("store", args), func() (string, error) {
+		return c.client.CallStore(ctx, args...)
+	})
+}
+
+func (c instrumentedAdminClient) MaintenanceStore(ctx context.Context, action admin.MaintenanceAction, args ...string) (string, error) {
+	return observeAdminCall("store maintenance "+string(action), func() (string, error) {
+		return c.client.MaintenanceStore(ctx, action, args...)
+	})
+}
+
+func (c instrumentedAdminClient) GetHMetaStatus(ctx context.Context) (status admin.HMetaStatus, err error) {
+	_, err = observeAdminCall("hmeta status", func() (string, error) {
+		status, err = c.client.GetHMetaStatus(ctx)
+		return "", err
+	})
+	return
+}
+
+func observeAdminCall(command string, call func() (string, error)) (string, error) {
+	start := time.Now()
+	output, err := call()
+	adminCallDuration.WithLabelValues(command).Observe(time.Since(start).Seconds())
+	if err != nil {
+		adminCallFailuresTotal.WithLabelValues(command).Inc()
+	}
+	return output, err
+}
+
+// adminCommands lists the admin commands known to the metrics, mapping each
+// command to its subcommands.
+var adminCommands = map[string]map[string][]string{
+	"server": {
+		"init":    nil,
+		"status":  nil,
+		"version": nil,
+		"sql":     nil,
+		"stream":  {"list", "create", "delete"},
+		"sub":     {"list", "create", "delete"},
+		"query":   {"list", "terminate", "resume", "delete"},
+		"view":    {"list", "delete"},
+	},
+	"store": {
+		"status":       nil,
+		"nodes-config": {"bootstrap"},
+	},
+}
+
+// adminCommand names an admin command by its fixed verbs only. Arguments such
+// as resource names, IDs or SQL statements are never part of the name, so the
+// cardinality of the metrics stays bounded. Unknown commands are named "other".
+func adminCommand(prefix string, args []string) string {
+	if len(args) == 0 {
+		return prefix
+	}
+	subcommands, ok := adminCommands[prefix][args[0]]
+	if !ok {
+		return prefix + " other"
+	}
+	parts := []string{prefix, args[0]}
+	if len(args) > 1 {
+		for _, sub := range subcommands {
+			if args[1] == sub {
+				parts = append(parts, sub)
+				break
+			}
+		}
+	}
+	return strings.Join(parts, " ")
+}
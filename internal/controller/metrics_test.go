@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"github.com/hstreamdb/hstream-operator/internal/admin"
+	"github.com/hstreamdb/hstream-operator/mock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("controller/metrics", func() {
+	It("should name admin commands by their verbs only", func() {
+		Expect(adminCommand("store", []string{"nodes-config", "bootstrap", "--metadata-replicate-across", "node:3"})).
+			To(Equal("store nodes-config bootstrap"))
+		Expect(adminCommand("server", []string{"init", "--host", "hserver"})).To(Equal("server init"))
+		Expect(adminCommand("server", []string{"sql", "SELECT * FROM s EMIT CHANGES;"})).To(Equal("server sql"))
+		Expect(adminCommand("server", []string{"stream", "delete", "orders"})).To(Equal("server stream delete"))
+		Expect(adminCommand("server", []string{"sub", "orders-sub"})).To(Equal("server sub"))
+		Expect(adminCommand("server", []string{"orders"})).To(Equal("server other"))
+	})
+
+	It("should count failed admin calls", func() {
+		client := instrumentedAdminClient{client: failingAdminClient{}}
+		before := testutil.ToFloat64(adminCallFailuresTotal.WithLabelValues("server init"))
+
+		_, err := client.CallServer(context.TODO(), "init", "--host", "hserver")
+		Expect(err).To(HaveOccurred())
+		Expect(testutil.ToFloat64(adminCallFailuresTotal.WithLabelValues("server init"))).To(Equal(before + 1))
+	})
+
+	It("should record readiness of components", func() {
+		hdb := mock.CreateDefaultCR()
+		hdb.SetCondition(metav1.Condition{Type: hapi.HStoreReady, Status: metav1.ConditionTrue, Reason: hapi.ReasonAllReplicasReady})
+		hdb.SetCondition(metav1.Condition{Type: hapi.HServerReady, Status: metav1.ConditionFalse, Reason: hapi.ReasonReplicasNotReady})
+		hdb.Status.HMeta.Nodes = []hapi.HMetaNode{
+			{NodeId: "0", Reachable: true, Leader: true},
+			{NodeId: "1", Reachable: true},
+			{NodeId: "2"},
+		}
+
+		recordClusterMetrics(hdb)
+		Expect(testutil.ToFloat64(componentReady.WithLabelValues(hdb.Namespace, hdb.Name, string(hapi.ComponentTypeHStore)))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(componentReady.WithLabelValues(hdb.Namespace, hdb.Name, string(hapi.ComponentTypeHServer)))).To(Equal(0.0))
+		Expect(testutil.ToFloat64(hmetaReachableNodes.WithLabelValues(hdb.Namespace, hdb.Name))).To(Equal(2.0))
+		Expect(testutil.ToFloat64(hmetaLeaderNodes.WithLabelValues(hdb.Namespace, hdb.Name))).To(Equal(1.0))
+
+		deleteClusterMetrics(hdb.Namespace, hdb.Name)
+		Expect(componentReady.DeleteLabelValues(hdb.Namespace, hdb.Name, string(hapi.ComponentTypeHStore))).To(BeFalse())
+	})
+})
+
+type failingAdminClient struct{}
+
+func (failingAdminClient) CallServer(context.Context, ...string) (string, error) {
+	return "", errors.New("exec failed")
+}
+
+func (failingAdminClient) CallStore(context.Context, ...string) (string, error) {
+	return "", errors.New("exec failed")
+}
+
+func (failingAdminClient) MaintenanceStore(context.Context, admin.MaintenanceAction, ...string) (string, error) {
+	return "", errors.New("exec failed")
+}
+
+func (failingAdminClient) GetHMetaStatus(context.Context) (admin.HMetaStatus, error) {
+	return admin.HMetaStatus{}, errors.New("exec failed")
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// isStepPaused reports whether the step must not run because of spec.pause.
+// Final and status-only steps always run, so that the status stays accurate.
+func isStepPaused(pause *hapi.Pause, step subReconcilerStep) bool {
+	if pause == nil || step.final || step.statusOnly {
+		return false
+	}
+	if len(pause.Components) == 0 {
+		return true
+	}
+	return step.component != "" && pause.IsPaused(step.component)
+}
+
+// updatePausedCondition reflects spec.pause in the Paused condition, and
+// records an event when the reconciliation is paused or resumed.
+func (r *HStreamDBReconciler) updatePausedCondition(hdb *hapi.HStreamDB) {
+	wasPaused := hdb.IsConditionTrue(hapi.Paused)
+
+	if hdb.Spec.Pause == nil {
+		if !wasPaused {
+			return
+		}
+		hdb.SetCondition(metav1.Condition{
+			Type:    hapi.Paused,
+			Status:  metav1.ConditionFalse,
+			Reason:  hapi.ReasonResumed,
+			Message: "Reconciliation is resumed",
+		})
+		r.Recorder.Event(hdb, corev1.EventTypeNormal, hapi.ReasonResumed, "Reconciliation is resumed")
+		return
+	}
+
+	message := "Reconciliation of all components is paused"
+	if components := hdb.Spec.Pause.Components; len(components) > 0 {
+		names := make([]string, 0, len(components))
+		for _, c := range components {
+			names = append(names, string(c))
+		}
+		message = fmt.Sprintf("Reconciliation of %s is paused", strings.Join(names, ", "))
+	}
+
+	_, old := hdb.GetCondition(hapi.Paused)
+	hdb.SetCondition(metav1.Condition{
+		Type:    hapi.Paused,
+		Status:  metav1.ConditionTrue,
+		Reason:  hapi.ReasonPaused,
+		Message: message,
+	})
+	if !wasPaused || old.Message != message {
+		r.Recorder.Event(hdb, corev1.EventTypeNormal, hapi.ReasonPaused, message)
+	}
+}
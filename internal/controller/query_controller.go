@@ -0,0 +1,287 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"github.com/hstreamdb/hstream-operator/internal/admin"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// queryFinalizer removes the query from HStreamDB, it is only added to
+	// the Queries with the Delete deletion policy.
+	queryFinalizer = "apps.hstream.io/query"
+
+	// sqlResyncPeriod defines how often the task of a query or view is
+	// observed again, so that an aborted task is restarted.
+	sqlResyncPeriod = time.Minute
+)
+
+// hashSQL returns the hash of a statement recorded in the status, so that a
+// change of the statement is noticed without keeping it twice.
+func hashSQL(sql string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(sql)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// observeSQLTask records the task of a query or view in the status, and
+// returns the Ready condition describing it.
+func observeSQLTask(status *hapi.SQLTaskStatus, query *admin.Query) metav1.Condition {
+	status.QueryID = query.ID
+	status.NodeID = query.NodeID
+	status.TaskStatus = query.Status
+
+	if query.IsRunning() {
+		return metav1.Condition{
+			Type:    hapi.Ready,
+			Status:  metav1.ConditionTrue,
+			Reason:  hapi.ReasonQueryRunning,
+			Message: fmt.Sprintf("Query %s is running", query.ID),
+		}
+	}
+	return metav1.Condition{
+		Type:    hapi.Ready,
+		Status:  metav1.ConditionFalse,
+		Reason:  hapi.ReasonQueryNotRunning,
+		Message: fmt.Sprintf("Query %s is %s", query.ID, query.Status),
+	}
+}
+
+// QueryReconciler reconciles a Query object
+type QueryReconciler struct {
+	client.Client
+	Scheme              *runtime.Scheme
+	Recorder            record.EventRecorder
+	AdminClientProvider admin.AdminClientProvider
+}
+
+//+kubebuilder:rbac:groups=apps.hstream.io,resources=queries,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.hstream.io,resources=queries/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=apps.hstream.io,resources=queries/finalizers,verbs=update
+
+// Reconcile submits the statement of a Query to its HStreamDB, recreates the
+// query once the statement changes and restarts it once aborted.
+func (r *QueryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	query := &hapi.Query{}
+	if err := r.Get(ctx, req.NamespacedName, query); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !query.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, query)
+	}
+
+	if err := r.syncFinalizer(ctx, query); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	hdbName := query.Spec.HStreamDBRef.Name
+	hdb, err := getHStreamDB(ctx, r.Client, query.Namespace, query.Spec.HStreamDBRef)
+	if err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, r.updateStatus(ctx, query, hapi.SQLStatePending, metav1.Condition{
+			Status: metav1.ConditionFalse, Reason: hapi.ReasonHStreamDBNotFound,
+			Message: fmt.Sprintf("HStreamDB %s not found", hdbName),
+		})
+	}
+	if !isHStreamDBReady(hdb) {
+		return ctrl.Result{}, r.updateStatus(ctx, query, hapi.SQLStatePending, metav1.Condition{
+			Status: metav1.ConditionFalse, Reason: hapi.ReasonHStreamDBNotReady,
+			Message: fmt.Sprintf("HStreamDB %s is not ready", hdbName),
+		})
+	}
+
+	adminClient := admin.NewTypedAdminClient(r.AdminClientProvider.GetAdminClient(hdb))
+	observed, err := r.ensureQuery(ctx, adminClient, query)
+	if err != nil {
+		r.Recorder.Event(query, corev1.EventTypeWarning, hapi.ReasonAdminCommandFailed, err.Error())
+		return ctrl.Result{}, utilerrors.NewAggregate([]error{err,
+			r.updateStatus(ctx, query, hapi.SQLStateFailed, metav1.Condition{
+				Status: metav1.ConditionFalse, Reason: hapi.ReasonAdminCommandFailed, Message: err.Error(),
+			}),
+		})
+	}
+
+	condition := observeSQLTask(&query.Status.SQLTaskStatus, observed)
+	return ctrl.Result{RequeueAfter: sqlResyncPeriod}, r.updateStatus(ctx, query, hapi.SQLStateCreated, condition)
+}
+
+// ensureQuery submits the statement unless a query with the same statement
+// exists, and returns the query observed in HStreamDB.
+func (r *QueryReconciler) ensureQuery(ctx context.Context, adminClient admin.TypedAdminClient, query *hapi.Query) (*admin.Query, error) {
+	hash := hashSQL(query.Spec.SQL)
+
+	var observed *admin.Query
+	var err error
+	if id := query.Status.QueryID; id != "" {
+		if observed, err = adminClient.GetQuery(ctx, id); err != nil {
+			return nil, fmt.Errorf("failed to get query %s: %w", id, err)
+		}
+	} else if observed, err = adminClient.FindQueryBySQL(ctx, query.Spec.SQL); err != nil {
+		// The query may have been submitted without recording its ID.
+		return nil, fmt.Errorf("failed to list queries: %w", err)
+	} else if observed != nil {
+		query.Status.SQLHash = hash
+	}
+
+	if observed != nil && query.Status.SQLHash != hash {
+		if err = adminClient.RemoveQuery(ctx, observed); err != nil {
+			return nil, err
+		}
+		r.Recorder.Eventf(query, corev1.EventTypeNormal, hapi.ReasonQueryRecreated,
+			"Removed query %s since the statement changed", observed.ID)
+		observed = nil
+	}
+
+	if observed == nil {
+		if observed, err = adminClient.CreateQuery(ctx, query.Spec.SQL); err != nil {
+			return nil, fmt.Errorf("failed to create query: %w", err)
+		}
+		query.Status.SQLHash = hash
+		r.Recorder.Eventf(query, corev1.EventTypeNormal, hapi.ReasonQueryCreated,
+			"Created query %s in HStreamDB %s", observed.ID, query.Spec.HStreamDBRef.Name)
+		return observed, nil
+	}
+
+	if observed.IsAborted() {
+		id := observed.ID
+		if err = adminClient.ResumeQuery(ctx, id); err != nil {
+			return nil, fmt.Errorf("failed to restart query %s: %w", id, err)
+		}
+		r.Recorder.Eventf(query, corev1.EventTypeNormal, hapi.ReasonQueryRestarted,
+			"Restarted query %s which was %s", id, observed.Status)
+		if observed, err = adminClient.GetQuery(ctx, id); err != nil {
+			return nil, fmt.Errorf("failed to get query %s: %w", id, err)
+		}
+		if observed == nil {
+			return nil, fmt.Errorf("query %s not found after restart", id)
+		}
+	}
+	return observed, nil
+}
+
+// syncFinalizer makes sure the finalizer is only present with the Delete
+// deletion policy.
+func (r *QueryReconciler) syncFinalizer(ctx context.Context, query *hapi.Query) error {
+	var updated bool
+	if query.Spec.DeletionPolicy == hapi.DeletionPolicyDelete {
+		updated = controllerutil.AddFinalizer(query, queryFinalizer)
+	} else {
+		updated = controllerutil.RemoveFinalizer(query, queryFinalizer)
+	}
+	if !updated {
+		return nil
+	}
+	return r.Update(ctx, query)
+}
+
+// finalize removes the query from HStreamDB according to the deletion policy.
+func (r *QueryReconciler) finalize(ctx context.Context, query *hapi.Query) error {
+	if !controllerutil.ContainsFinalizer(query, queryFinalizer) {
+		return nil
+	}
+
+	if query.Spec.DeletionPolicy == hapi.DeletionPolicyDelete && query.Status.QueryID != "" {
+		if err := r.removeQuery(ctx, query); err != nil {
+			r.Recorder.Event(query, corev1.EventTypeWarning, hapi.ReasonAdminCommandFailed, err.Error())
+			return err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(query, queryFinalizer)
+	return r.Update(ctx, query)
+}
+
+func (r *QueryReconciler) removeQuery(ctx context.Context, query *hapi.Query) error {
+	hdb, err := getHStreamDB(ctx, r.Client, query.Namespace, query.Spec.HStreamDBRef)
+	if err != nil {
+		// The query is gone together with the HStreamDB.
+		return client.IgnoreNotFound(err)
+	}
+	if !hdb.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	id := query.Status.QueryID
+	adminClient := admin.NewTypedAdminClient(r.AdminClientProvider.GetAdminClient(hdb))
+	observed, err := adminClient.GetQuery(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get query %s: %w", id, err)
+	}
+	if observed == nil {
+		return nil
+	}
+	if err = adminClient.RemoveQuery(ctx, observed); err != nil {
+		return err
+	}
+	r.Recorder.Eventf(query, corev1.EventTypeNormal, hapi.ReasonQueryDeleted,
+		"Deleted query %s from HStreamDB %s", id, hdb.Name)
+	return nil
+}
+
+// updateStatus sets the Ready condition, whose type and observed generation
+// are filled in here.
+func (r *QueryReconciler) updateStatus(ctx context.Context, query *hapi.Query, state hapi.SQLState, condition metav1.Condition) error {
+	query.Status.State = state
+	query.Status.ObservedGeneration = query.Generation
+	condition.Type = hapi.Ready
+	condition.ObservedGeneration = query.Generation
+	meta.SetStatusCondition(&query.Status.Conditions, condition)
+	return r.Status().Update(ctx, query)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *QueryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.AdminClientProvider = instrumentedAdminClientProvider{r.AdminClientProvider}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &hapi.Query{}, hstreamDBRefField,
+		func(obj client.Object) []string {
+			return []string{hstreamDBRefKey(obj.GetNamespace(), obj.(*hapi.Query).Spec.HStreamDBRef)}
+		}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&hapi.Query{}).
+		// Submit the queries once their HStreamDB becomes ready.
+		Watches(&source.Kind{Type: &hapi.HStreamDB{}},
+			handler.EnqueueRequestsFromMapFunc(requestsForHStreamDB(mgr.GetClient(), &hapi.QueryList{})),
+			builder.WithPredicates(hstreamDBReadinessChanged)).
+		Complete(r)
+}
@@ -0,0 +1,136 @@
+package controller
+
+import (
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"github.com/hstreamdb/hstream-operator/internal/admin"
+	"github.com/hstreamdb/hstream-operator/mock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("controller/query", Ordered, func() {
+	const namespace = "query-test"
+	const sql = "CREATE STREAM large_orders AS SELECT * FROM orders WHERE amount > 100;"
+	var hdb *hapi.HStreamDB
+	var server *fakeServerAdminClient
+	var reconciler *QueryReconciler
+
+	newQuery := func(name string) *hapi.Query {
+		return &hapi.Query{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: hapi.QuerySpec{
+				HStreamDBRef: hapi.HStreamDBReference{Name: hdb.Name},
+				SQL:          sql,
+			},
+		}
+	}
+
+	reconcileQuery := func(query *hapi.Query) (ctrl.Result, error) {
+		res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(query)})
+		if getErr := k8sClient.Get(ctx, client.ObjectKeyFromObject(query), query); getErr != nil && !k8sErrors.IsNotFound(getErr) {
+			Expect(getErr).To(Succeed())
+		}
+		return res, err
+	}
+
+	BeforeAll(func() {
+		Expect(k8sClient.Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: namespace},
+		})).To(Succeed())
+
+		hdb = mock.CreateDefaultCR()
+		hdb.Name = "query-hdb"
+		hdb.Namespace = namespace
+		Expect(k8sClient.Create(ctx, hdb)).To(Succeed())
+		for _, condition := range []string{hapi.HServerReady, hapi.AdminServerReady} {
+			hdb.SetCondition(metav1.Condition{Type: condition, Status: metav1.ConditionTrue, Reason: "test"})
+		}
+		Expect(k8sClient.Status().Update(ctx, hdb)).To(Succeed())
+	})
+
+	BeforeEach(func() {
+		server = newFakeServerAdminClient()
+		reconciler = &QueryReconciler{
+			Client:              k8sClient,
+			Scheme:              k8sClient.Scheme(),
+			Recorder:            record.NewFakeRecorder(100),
+			AdminClientProvider: fakeAdminClientProvider{client: server},
+		}
+	})
+
+	It("should submit the query and report its task", func() {
+		query := newQuery("large-orders")
+		Expect(k8sClient.Create(ctx, query)).To(Succeed())
+
+		res, err := reconcileQuery(query)
+		Expect(err).To(Succeed())
+		Expect(res.RequeueAfter).To(Equal(sqlResyncPeriod))
+		Expect(server.commands).To(ContainElement("sql " + sql))
+		Expect(server.queries).To(HaveLen(1))
+		Expect(query.Status.State).To(Equal(hapi.SQLStateCreated))
+		Expect(query.Status.QueryID).NotTo(BeEmpty())
+		Expect(query.Status.NodeID).To(BeEquivalentTo(100))
+		Expect(query.Status.TaskStatus).To(Equal("TASK_RUNNING"))
+		Expect(query.Status.SQLHash).To(Equal(hashSQL(sql)))
+		Expect(query.Status.Conditions).To(ContainElement(And(
+			HaveField("Type", hapi.Ready),
+			HaveField("Status", metav1.ConditionTrue),
+			HaveField("Reason", hapi.ReasonQueryRunning),
+		)))
+
+		By("restarting the aborted query")
+		id := query.Status.QueryID
+		observed := server.queries[id]
+		observed.Status = "TASK_ABORTED"
+		server.queries[id] = observed
+
+		_, err = reconcileQuery(query)
+		Expect(err).To(Succeed())
+		Expect(server.commands).To(ContainElement("query resume " + id))
+		Expect(query.Status.QueryID).To(Equal(id))
+		Expect(query.Status.TaskStatus).To(Equal("TASK_RUNNING"))
+
+		By("recreating the query once the statement changes")
+		query.Spec.SQL = "CREATE STREAM large_orders AS SELECT * FROM orders WHERE amount > 500;"
+		Expect(k8sClient.Update(ctx, query)).To(Succeed())
+
+		_, err = reconcileQuery(query)
+		Expect(err).To(Succeed())
+		Expect(server.commands).To(ContainElements("query terminate "+id, "query delete "+id))
+		Expect(server.queries).To(HaveLen(1))
+		Expect(query.Status.QueryID).NotTo(Equal(id))
+		Expect(query.Status.SQLHash).To(Equal(hashSQL(query.Spec.SQL)))
+	})
+
+	It("should adopt the query submitted with the same statement", func() {
+		server.queries["cli_generated_a1b2c"] = admin.Query{ID: "cli_generated_a1b2c", Status: "TASK_RUNNING", SQL: sql}
+		query := newQuery("adopted")
+		Expect(k8sClient.Create(ctx, query)).To(Succeed())
+
+		_, err := reconcileQuery(query)
+		Expect(err).To(Succeed())
+		Expect(server.commands).To(Equal([]string{"query list"}))
+		Expect(query.Status.QueryID).To(Equal("cli_generated_a1b2c"))
+	})
+
+	It("should delete the query according to the deletion policy", func() {
+		query := newQuery("deleted")
+		query.Spec.DeletionPolicy = hapi.DeletionPolicyDelete
+		Expect(k8sClient.Create(ctx, query)).To(Succeed())
+		_, err := reconcileQuery(query)
+		Expect(err).To(Succeed())
+		Expect(query.Finalizers).To(ContainElement(queryFinalizer))
+
+		Expect(k8sClient.Delete(ctx, query)).To(Succeed())
+		_, err = reconcileQuery(query)
+		Expect(err).To(Succeed())
+		Expect(server.queries).To(BeEmpty())
+		Expect(k8sErrors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(query), query))).To(BeTrue())
+	})
+})
@@ -1,10 +1,10 @@
 package controller
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/hstreamdb/hstream-operator/internal/admin"
 	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -25,28 +25,102 @@ type requeue struct {
 
 	// delayedRequeue defines that the reconciliation was not completed but the requeue should be delayed to the end.
 	delayedRequeue bool
+
+	// reason categorizes the requeue, it is used as the reason of events,
+	// conditions and metrics.
+	reason requeueReason
+}
+
+// requeueReason is a typed reason of a requeue.
+type requeueReason string
+
+// Reasons of waits, they are expected while the cluster is starting or updating.
+const (
+	reasonTerminatedEarly    requeueReason = "ReconciliationTerminatedEarly"
+	reasonWaitingForPods     requeueReason = "WaitingForPods"
+	reasonWaitingForHServer  requeueReason = "WaitingForHServer"
+	reasonHMetaNotReady      requeueReason = "HMetaNotReady"
+	reasonClusterNotReady    requeueReason = "ClusterNotReady"
+	reasonResourceConflict   requeueReason = "ResourceConflict"
+	reasonBootstrapSucceeded requeueReason = "BootstrapSucceeded"
+)
+
+// Reasons of failures, they are reported with warning events and the
+// ReconcileError condition.
+const (
+	reasonReconcileFailed    requeueReason = "ReconcileFailed"
+	reasonHMetaUnreachable   requeueReason = "HMetaUnreachable"
+	reasonBootstrapFailed    requeueReason = "BootstrapFailed"
+	reasonAdminExecFailed    requeueReason = "AdminExecFailed"
+	reasonApplyFailed        requeueReason = "ResourceApplyFailed"
+	reasonStatusUpdateFailed requeueReason = "StatusUpdateFailed"
+)
+
+var failureReasons = map[requeueReason]bool{
+	reasonReconcileFailed:    true,
+	reasonHMetaUnreachable:   true,
+	reasonBootstrapFailed:    true,
+	reasonAdminExecFailed:    true,
+	reasonApplyFailed:        true,
+	reasonStatusUpdateFailed: true,
+}
+
+// adminFailureReason returns the given reason if the admin command ran but
+// failed, and reasonAdminExecFailed if it could not be run at all.
+func adminFailureReason(err error, commandFailed requeueReason) requeueReason {
+	if admin.IsCommandFailed(err) {
+		return commandFailed
+	}
+	return reasonAdminExecFailed
+}
+
+// effectiveReason returns the reason of the requeue. Conflicts are expected
+// with concurrent updates and are never treated as failures.
+func (r *requeue) effectiveReason() requeueReason {
+	switch {
+	case r.curError != nil && k8sErrors.IsConflict(r.curError):
+		return reasonResourceConflict
+	case r.reason != "":
+		return r.reason
+	case r.curError != nil:
+		return reasonReconcileFailed
+	default:
+		return reasonTerminatedEarly
+	}
+}
+
+// isFailure reports whether the requeue is caused by a failure rather than
+// by waiting for the cluster.
+func (r *requeue) isFailure() bool {
+	return failureReasons[r.effectiveReason()]
 }
 
 // processRequeue interprets a requeue result from a subreconciler.
-func processRequeue(requeue *requeue, subReconciler interface{}, object runtime.Object,
+func processRequeue(requeue *requeue, subReconciler string, object runtime.Object,
 	recorder record.EventRecorder, logger logr.Logger) (ctrl.Result, error) {
 
-	curLog := logger.WithValues("subReconciler",
-		fmt.Sprintf("%T", subReconciler), "requeueAfter", requeue.delay)
+	curLog := logger.WithValues("subReconciler", subReconciler, "requeueAfter", requeue.delay)
 
 	if requeue.message == "" && requeue.curError != nil {
 		requeue.message = requeue.curError.Error()
 	}
 
+	reason := requeue.effectiveReason()
+	requeueTotal.WithLabelValues(subReconciler, string(reason)).Inc()
+
 	err := requeue.curError
-	if err != nil && k8sErrors.IsConflict(err) {
+	if reason == reasonResourceConflict {
 		err = nil
 		if requeue.delay == time.Duration(0) {
 			requeue.delay = time.Second
 		}
 	}
 
-	recorder.Event(object, corev1.EventTypeNormal, "ReconciliationTerminatedEarly", requeue.message)
+	eventType := corev1.EventTypeNormal
+	if requeue.isFailure() {
+		eventType = corev1.EventTypeWarning
+	}
+	recorder.Event(object, eventType, string(reason), requeue.message)
 
 	if err != nil {
 		curLog.Error(err, "Error in reconciliation")
@@ -0,0 +1,266 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"github.com/hstreamdb/hstream-operator/internal/admin"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// streamFinalizer deletes the stream from HStreamDB, it is only added to
+	// the Streams with the Delete deletion policy.
+	streamFinalizer = "apps.hstream.io/stream"
+
+	// streamResyncPeriod defines how often a created stream is observed again,
+	// so that the streams changed out of band are reported.
+	streamResyncPeriod = 5 * time.Minute
+)
+
+// StreamReconciler reconciles a Stream object
+type StreamReconciler struct {
+	client.Client
+	Scheme              *runtime.Scheme
+	Recorder            record.EventRecorder
+	AdminClientProvider admin.AdminClientProvider
+}
+
+//+kubebuilder:rbac:groups=apps.hstream.io,resources=streams,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.hstream.io,resources=streams/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=apps.hstream.io,resources=streams/finalizers,verbs=update
+
+// Reconcile creates the stream of a Stream in its HStreamDB, and reports the
+// settings of the stream observed in HStreamDB.
+func (r *StreamReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	stream := &hapi.Stream{}
+	if err := r.Get(ctx, req.NamespacedName, stream); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !stream.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, stream)
+	}
+
+	if err := r.syncFinalizer(ctx, stream); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	hdbName := stream.Spec.HStreamDBRef.Name
+	hdb, err := getHStreamDB(ctx, r.Client, stream.Namespace, stream.Spec.HStreamDBRef)
+	if err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, r.updateStatus(ctx, stream, hapi.StreamStatePending, metav1.ConditionFalse,
+			hapi.ReasonHStreamDBNotFound, fmt.Sprintf("HStreamDB %s not found", hdbName))
+	}
+	if !isHStreamDBReady(hdb) {
+		return ctrl.Result{}, r.updateStatus(ctx, stream, hapi.StreamStatePending, metav1.ConditionFalse,
+			hapi.ReasonHStreamDBNotReady, fmt.Sprintf("HStreamDB %s is not ready", hdbName))
+	}
+
+	adminClient := admin.NewTypedAdminClient(r.AdminClientProvider.GetAdminClient(hdb))
+	observed, err := r.ensureStream(ctx, adminClient, stream)
+	if err != nil {
+		r.Recorder.Event(stream, corev1.EventTypeWarning, hapi.ReasonAdminCommandFailed, err.Error())
+		return ctrl.Result{}, utilerrors.NewAggregate([]error{err,
+			r.updateStatus(ctx, stream, hapi.StreamStateFailed, metav1.ConditionFalse, hapi.ReasonAdminCommandFailed, err.Error()),
+		})
+	}
+
+	stream.Status.ReplicationFactor = observed.ReplicationFactor
+	stream.Status.ShardCount = observed.ShardCount
+	stream.Status.BacklogRetention = nil
+	if observed.BacklogDuration > 0 {
+		stream.Status.BacklogRetention = &metav1.Duration{Duration: observed.BacklogDuration}
+	}
+
+	status, reason, message := metav1.ConditionTrue, hapi.ReasonStreamCreated, fmt.Sprintf("Stream %s is created", observed.Name)
+	if diffs := streamSettingsDiff(stream.Spec, observed); len(diffs) > 0 {
+		// The stream is usable, but it is not the one declared by the spec.
+		status = metav1.ConditionFalse
+		reason = hapi.ReasonStreamSettingsDiffer
+		message = fmt.Sprintf("Settings of stream %s cannot be changed once created: %s",
+			observed.Name, strings.Join(diffs, ", "))
+		if old := meta.FindStatusCondition(stream.Status.Conditions, hapi.Ready); old == nil || old.Message != message {
+			r.Recorder.Event(stream, corev1.EventTypeWarning, reason, message)
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: streamResyncPeriod},
+		r.updateStatus(ctx, stream, hapi.StreamStateCreated, status, reason, message)
+}
+
+// ensureStream creates the stream if it does not exist yet, and returns the
+// stream observed in HStreamDB.
+func (r *StreamReconciler) ensureStream(ctx context.Context, adminClient admin.TypedAdminClient, stream *hapi.Stream) (*admin.Stream, error) {
+	name := stream.GetStreamName()
+	observed, err := adminClient.GetStream(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stream %s: %w", name, err)
+	}
+	if observed != nil {
+		return observed, nil
+	}
+
+	opts := admin.StreamOptions{
+		ReplicationFactor: stream.Spec.ReplicationFactor,
+		ShardCount:        stream.Spec.ShardCount,
+	}
+	if stream.Spec.BacklogRetention != nil {
+		opts.BacklogDuration = stream.Spec.BacklogRetention.Duration
+	}
+	if err = adminClient.CreateStream(ctx, name, opts); err != nil {
+		return nil, fmt.Errorf("failed to create stream %s: %w", name, err)
+	}
+	r.Recorder.Eventf(stream, corev1.EventTypeNormal, hapi.ReasonStreamCreated,
+		"Created stream %s in HStreamDB %s", name, stream.Spec.HStreamDBRef.Name)
+
+	if observed, err = adminClient.GetStream(ctx, name); err != nil {
+		return nil, fmt.Errorf("failed to get stream %s: %w", name, err)
+	}
+	if observed == nil {
+		return nil, fmt.Errorf("stream %s not found after creation", name)
+	}
+	return observed, nil
+}
+
+// streamSettingsDiff describes the settings of the stream in HStreamDB which
+// differ from the spec, since a stream cannot be altered once created.
+func streamSettingsDiff(spec hapi.StreamSpec, observed *admin.Stream) (diffs []string) {
+	if spec.ReplicationFactor != observed.ReplicationFactor {
+		diffs = append(diffs, fmt.Sprintf("replicationFactor is %d", observed.ReplicationFactor))
+	}
+	if spec.ShardCount != observed.ShardCount {
+		diffs = append(diffs, fmt.Sprintf("shardCount is %d", observed.ShardCount))
+	}
+	if spec.BacklogRetention != nil && spec.BacklogRetention.Duration != observed.BacklogDuration {
+		diffs = append(diffs, fmt.Sprintf("backlogRetention is %s", observed.BacklogDuration))
+	}
+	return
+}
+
+// syncFinalizer makes sure the finalizer is only present with the Delete
+// deletion policy, so that retained streams never block the deletion.
+func (r *StreamReconciler) syncFinalizer(ctx context.Context, stream *hapi.Stream) error {
+	var updated bool
+	if stream.Spec.DeletionPolicy == hapi.DeletionPolicyDelete {
+		updated = controllerutil.AddFinalizer(stream, streamFinalizer)
+	} else {
+		updated = controllerutil.RemoveFinalizer(stream, streamFinalizer)
+	}
+	if !updated {
+		return nil
+	}
+	return r.Update(ctx, stream)
+}
+
+// finalize deletes the stream from HStreamDB according to the deletion policy.
+func (r *StreamReconciler) finalize(ctx context.Context, stream *hapi.Stream) error {
+	if !controllerutil.ContainsFinalizer(stream, streamFinalizer) {
+		return nil
+	}
+
+	if stream.Spec.DeletionPolicy == hapi.DeletionPolicyDelete {
+		if err := r.deleteStream(ctx, stream); err != nil {
+			r.Recorder.Event(stream, corev1.EventTypeWarning, hapi.ReasonAdminCommandFailed, err.Error())
+			return err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(stream, streamFinalizer)
+	return r.Update(ctx, stream)
+}
+
+func (r *StreamReconciler) deleteStream(ctx context.Context, stream *hapi.Stream) error {
+	hdb, err := getHStreamDB(ctx, r.Client, stream.Namespace, stream.Spec.HStreamDBRef)
+	if err != nil {
+		// The stream is gone together with the HStreamDB.
+		return client.IgnoreNotFound(err)
+	}
+	if !hdb.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	name := stream.GetStreamName()
+	adminClient := admin.NewTypedAdminClient(r.AdminClientProvider.GetAdminClient(hdb))
+	observed, err := adminClient.GetStream(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to get stream %s: %w", name, err)
+	}
+	if observed == nil {
+		return nil
+	}
+	if err = adminClient.DeleteStream(ctx, name); err != nil {
+		return fmt.Errorf("failed to delete stream %s: %w", name, err)
+	}
+	r.Recorder.Eventf(stream, corev1.EventTypeNormal, hapi.ReasonStreamDeleted,
+		"Deleted stream %s from HStreamDB %s", name, hdb.Name)
+	return nil
+}
+
+func (r *StreamReconciler) updateStatus(ctx context.Context, stream *hapi.Stream, state hapi.StreamState,
+	status metav1.ConditionStatus, reason, message string) error {
+	stream.Status.State = state
+	stream.Status.ObservedGeneration = stream.Generation
+	meta.SetStatusCondition(&stream.Status.Conditions, metav1.Condition{
+		Type:               hapi.Ready,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: stream.Generation,
+	})
+	return r.Status().Update(ctx, stream)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *StreamReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.AdminClientProvider = instrumentedAdminClientProvider{r.AdminClientProvider}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &hapi.Stream{}, hstreamDBRefField,
+		func(obj client.Object) []string {
+			return []string{hstreamDBRefKey(obj.GetNamespace(), obj.(*hapi.Stream).Spec.HStreamDBRef)}
+		}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&hapi.Stream{}).
+		// Create the streams once their HStreamDB becomes ready.
+		Watches(&source.Kind{Type: &hapi.HStreamDB{}},
+			handler.EnqueueRequestsFromMapFunc(requestsForHStreamDB(mgr.GetClient(), &hapi.StreamList{})),
+			builder.WithPredicates(hstreamDBReadinessChanged)).
+		Complete(r)
+}
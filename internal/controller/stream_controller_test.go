@@ -0,0 +1,182 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"github.com/hstreamdb/hstream-operator/internal/admin"
+	"github.com/hstreamdb/hstream-operator/mock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("controller/stream", Ordered, func() {
+	const namespace = "stream-test"
+	var hdb *hapi.HStreamDB
+	var server *fakeServerAdminClient
+	var reconciler *StreamReconciler
+
+	newStream := func(name string) *hapi.Stream {
+		return &hapi.Stream{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: hapi.StreamSpec{
+				HStreamDBRef:      hapi.HStreamDBReference{Name: hdb.Name},
+				ReplicationFactor: 3,
+				ShardCount:        2,
+				BacklogRetention:  &metav1.Duration{Duration: 24 * time.Hour},
+			},
+		}
+	}
+
+	reconcileStream := func(stream *hapi.Stream) (ctrl.Result, error) {
+		res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(stream)})
+		if getErr := k8sClient.Get(ctx, client.ObjectKeyFromObject(stream), stream); getErr != nil && !k8sErrors.IsNotFound(getErr) {
+			Expect(getErr).To(Succeed())
+		}
+		return res, err
+	}
+
+	BeforeAll(func() {
+		Expect(k8sClient.Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: namespace},
+		})).To(Succeed())
+
+		hdb = mock.CreateDefaultCR()
+		hdb.Name = "stream-hdb"
+		hdb.Namespace = namespace
+	})
+
+	BeforeEach(func() {
+		server = newFakeServerAdminClient()
+		reconciler = &StreamReconciler{
+			Client:              k8sClient,
+			Scheme:              k8sClient.Scheme(),
+			Recorder:            record.NewFakeRecorder(100),
+			AdminClientProvider: fakeAdminClientProvider{client: server},
+		}
+	})
+
+	It("should wait for the HStreamDB", func() {
+		stream := newStream("pending")
+		Expect(k8sClient.Create(ctx, stream)).To(Succeed())
+
+		_, err := reconcileStream(stream)
+		Expect(err).To(Succeed())
+		Expect(stream.Status.State).To(Equal(hapi.StreamStatePending))
+		Expect(stream.Status.Conditions).To(ContainElement(And(
+			HaveField("Type", hapi.Ready),
+			HaveField("Status", metav1.ConditionFalse),
+			HaveField("Reason", hapi.ReasonHStreamDBNotFound),
+		)))
+
+		Expect(k8sClient.Create(ctx, hdb)).To(Succeed())
+		_, err = reconcileStream(stream)
+		Expect(err).To(Succeed())
+		Expect(stream.Status.Conditions).To(ContainElement(HaveField("Reason", hapi.ReasonHStreamDBNotReady)))
+		Expect(server.commands).To(BeEmpty())
+
+		for _, condition := range []string{hapi.HServerReady, hapi.AdminServerReady} {
+			hdb.SetCondition(metav1.Condition{Type: condition, Status: metav1.ConditionTrue, Reason: "test"})
+		}
+		Expect(k8sClient.Status().Update(ctx, hdb)).To(Succeed())
+	})
+
+	It("should create the stream and report its settings", func() {
+		stream := newStream("orders")
+		stream.Spec.StreamName = "orders-v1"
+		Expect(k8sClient.Create(ctx, stream)).To(Succeed())
+
+		res, err := reconcileStream(stream)
+		Expect(err).To(Succeed())
+		Expect(res.RequeueAfter).To(Equal(streamResyncPeriod))
+		Expect(server.commands).To(ContainElement("stream create orders-v1 --replication-factor 3 --shards 2 --backlog-duration 86400"))
+		Expect(stream.Status.State).To(Equal(hapi.StreamStateCreated))
+		Expect(stream.Status.ObservedGeneration).To(Equal(stream.Generation))
+		Expect(stream.Status.ReplicationFactor).To(BeEquivalentTo(3))
+		Expect(stream.Status.ShardCount).To(BeEquivalentTo(2))
+		Expect(stream.Status.BacklogRetention).To(Equal(&metav1.Duration{Duration: 24 * time.Hour}))
+		Expect(stream.Status.Conditions).To(ContainElement(And(
+			HaveField("Type", hapi.Ready),
+			HaveField("Status", metav1.ConditionTrue),
+			HaveField("Reason", hapi.ReasonStreamCreated),
+		)))
+
+		By("not creating the stream again")
+		server.commands = nil
+		_, err = reconcileStream(stream)
+		Expect(err).To(Succeed())
+		Expect(server.commands).To(Equal([]string{"stream list"}))
+	})
+
+	It("should reject changing the stream name", func() {
+		stream := newStream("renamed")
+		Expect(k8sClient.Create(ctx, stream)).To(Succeed())
+
+		stream.Spec.StreamName = "renamed-v2"
+		Expect(k8sClient.Update(ctx, stream)).To(MatchError(ContainSubstring("streamName is immutable")))
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(stream), stream)).To(Succeed())
+		stream.Spec.ShardCount = 3
+		Expect(k8sClient.Update(ctx, stream)).To(Succeed())
+	})
+
+	It("should report the settings that cannot be changed", func() {
+		server.streams["clicks"] = admin.Stream{Name: "clicks", ReplicationFactor: 1, ShardCount: 2}
+		stream := newStream("clicks")
+		Expect(k8sClient.Create(ctx, stream)).To(Succeed())
+
+		_, err := reconcileStream(stream)
+		Expect(err).To(Succeed())
+		Expect(stream.Status.ReplicationFactor).To(BeEquivalentTo(1))
+		Expect(stream.Status.BacklogRetention).To(BeNil())
+		Expect(stream.Status.State).To(Equal(hapi.StreamStateCreated))
+		Expect(stream.Status.Conditions).To(ContainElement(And(
+			HaveField("Type", hapi.Ready),
+			HaveField("Status", metav1.ConditionFalse),
+			HaveField("Reason", hapi.ReasonStreamSettingsDiffer),
+			HaveField("Message", ContainSubstring("replicationFactor is 1, backlogRetention is 0s")),
+		)))
+	})
+
+	It("should report the failures of admin commands", func() {
+		server.err = fmt.Errorf("no ready admin server pod")
+		stream := newStream("failed")
+		Expect(k8sClient.Create(ctx, stream)).To(Succeed())
+
+		_, err := reconcileStream(stream)
+		Expect(err).To(MatchError(ContainSubstring("no ready admin server pod")))
+		Expect(stream.Status.State).To(Equal(hapi.StreamStateFailed))
+		Expect(stream.Status.Conditions).To(ContainElement(HaveField("Reason", hapi.ReasonAdminCommandFailed)))
+	})
+
+	It("should delete the stream according to the deletion policy", func() {
+		retained := newStream("retained")
+		Expect(k8sClient.Create(ctx, retained)).To(Succeed())
+		_, err := reconcileStream(retained)
+		Expect(err).To(Succeed())
+		Expect(retained.Finalizers).To(BeEmpty())
+
+		deleted := newStream("deleted")
+		deleted.Spec.DeletionPolicy = hapi.DeletionPolicyDelete
+		Expect(k8sClient.Create(ctx, deleted)).To(Succeed())
+		_, err = reconcileStream(deleted)
+		Expect(err).To(Succeed())
+		Expect(deleted.Finalizers).To(ContainElement(streamFinalizer))
+
+		for _, stream := range []*hapi.Stream{retained, deleted} {
+			Expect(k8sClient.Delete(ctx, stream)).To(Succeed())
+			_, err = reconcileStream(stream)
+			Expect(err).To(Succeed())
+		}
+		Expect(server.streams).To(HaveKey("retained"))
+		Expect(server.streams).NotTo(HaveKey("deleted"))
+		Expect(k8sErrors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(deleted), deleted))).To(BeTrue())
+	})
+})
@@ -0,0 +1,351 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// subReconcilerStep is a node of the graph of sub-reconcilers.
+type subReconcilerStep struct {
+	// name identifies the step in dependencies and in the status.
+	name string
+
+	reconciler hdbSubReconciler
+
+	// dependsOn lists the names of the steps that must succeed before this
+	// step runs. Steps without dependencies start right away.
+	dependsOn []string
+
+	// final makes the step run after all other steps have finished, no matter
+	// whether they succeeded, e.g. updating the status.
+	final bool
+
+	// component is the component managed by the step, the step is paused when
+	// the reconciliation of the component is paused.
+	component hapi.ComponentType
+
+	// statusOnly marks the steps which only observe the cluster and update the
+	// status, they keep running while the reconciliation is paused.
+	statusOnly bool
+}
+
+// stepResult is the result of a finished step.
+type stepResult struct {
+	step    subReconcilerStep
+	requeue *requeue
+	outcome hapi.ReconcileStepOutcome
+
+	// duration is how long the step ran, zero if it did not run.
+	duration time.Duration
+	// paused is set if the step or one of the steps it depends on is paused.
+	paused bool
+}
+
+// satisfies reports whether the dependent step can run after this one. A paused
+// step, or one running after a paused step, only satisfies the status only
+// steps. The others must not run on top of a component which is left untouched,
+// while the status keeps being reported.
+func (s stepResult) satisfies(dependent subReconcilerStep) bool {
+	if s.paused && !dependent.statusOnly {
+		return false
+	}
+	switch s.outcome {
+	case hapi.ReconcileStepSucceeded, hapi.ReconcileStepDelayed, hapi.ReconcileStepPaused:
+		return true
+	default:
+		return false
+	}
+}
+
+func newStepResult(step subReconcilerStep, rq *requeue) stepResult {
+	result := stepResult{step: step, requeue: rq}
+	switch {
+	case rq == nil:
+		result.outcome = hapi.ReconcileStepSucceeded
+	case rq.delayedRequeue:
+		result.outcome = hapi.ReconcileStepDelayed
+	case rq.curError != nil:
+		result.outcome = hapi.ReconcileStepFailed
+	default:
+		result.outcome = hapi.ReconcileStepBlocked
+	}
+	return result
+}
+
+// runStep runs the reconciler of the step and measures its duration.
+func runStep(ctx context.Context, r *HStreamDBReconciler, hdb *hapi.HStreamDB, step subReconcilerStep) stepResult {
+	start := time.Now()
+	result := newStepResult(step, step.reconciler.reconcile(ctx, r, hdb))
+	result.duration = time.Since(start)
+	return result
+}
+
+// validateSteps makes sure that every dependency exists and that the graph
+// has no cycle, otherwise the scheduler would wait forever.
+func validateSteps(steps []subReconcilerStep) error {
+	byName := make(map[string]subReconcilerStep, len(steps))
+	for _, step := range steps {
+		if _, ok := byName[step.name]; ok {
+			return fmt.Errorf("duplicated sub-reconciler step %q", step.name)
+		}
+		byName[step.name] = step
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(steps))
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("sub-reconciler step %q is part of a dependency cycle", name)
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		for _, dep := range byName[name].dependsOn {
+			depStep, ok := byName[dep]
+			if !ok {
+				return fmt.Errorf("sub-reconciler step %q depends on unknown step %q", name, dep)
+			}
+			if depStep.final {
+				return fmt.Errorf("sub-reconciler step %q depends on final step %q", name, dep)
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		return nil
+	}
+
+	for _, step := range steps {
+		if err := visit(step.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runSteps runs the non-final steps as a DAG, independent branches run in
+// parallel. The results are returned in the order of the given steps.
+//
+// Every step works on its own copy of the HStreamDB, the status changes it
+// makes are merged back into hdb once it finishes.
+func (r *HStreamDBReconciler) runSteps(ctx context.Context, hdb *hapi.HStreamDB, steps []subReconcilerStep) []stepResult {
+	logger := log.WithValues("namespace", hdb.Namespace, "instance", hdb.Name)
+
+	type finished struct {
+		result   stepResult
+		snapshot *hapi.HStreamDB
+		updated  *hapi.HStreamDB
+	}
+
+	remaining := make(map[string]int)
+	dependents := make(map[string][]subReconcilerStep)
+	results := make(map[string]stepResult)
+	done := make(chan finished)
+	running := 0
+
+	start := func(step subReconcilerStep) {
+		running++
+		if isStepPaused(hdb.Spec.Pause, step) {
+			go func() {
+				done <- finished{
+					result: stepResult{
+						step:    step,
+						outcome: hapi.ReconcileStepPaused,
+						requeue: &requeue{message: "reconciliation is paused"},
+						paused:  true,
+					},
+				}
+			}()
+			return
+		}
+		paused := false
+		for _, dep := range step.dependsOn {
+			paused = paused || results[dep].paused
+		}
+		snapshot := hdb.DeepCopy()
+		updated := hdb.DeepCopy()
+		go func() {
+			logger.V(1).Info("Attempting to run sub-reconciler", "subReconciler", step.name)
+			result := runStep(ctx, r, updated, step)
+			result.paused = paused
+			done <- finished{result: result, snapshot: snapshot, updated: updated}
+		}()
+	}
+
+	// skip marks the step and all steps depending on it as skipped.
+	var skip func(step subReconcilerStep, cause string)
+	skip = func(step subReconcilerStep, cause string) {
+		if _, ok := results[step.name]; ok {
+			return
+		}
+		results[step.name] = stepResult{
+			step:    step,
+			outcome: hapi.ReconcileStepSkipped,
+			requeue: &requeue{message: fmt.Sprintf("dependency %s did not succeed", cause)},
+		}
+		for _, dependent := range dependents[step.name] {
+			skip(dependent, step.name)
+		}
+	}
+
+	for _, step := range steps {
+		if step.final {
+			continue
+		}
+		remaining[step.name] = len(step.dependsOn)
+		for _, dep := range step.dependsOn {
+			dependents[dep] = append(dependents[dep], step)
+		}
+	}
+	for _, step := range steps {
+		if !step.final && len(step.dependsOn) == 0 {
+			start(step)
+		}
+	}
+
+	for running > 0 {
+		f := <-done
+		running--
+		if f.updated != nil {
+			mergeStepStatus(hdb, f.snapshot, f.updated)
+		}
+		results[f.result.step.name] = f.result
+
+		for _, dependent := range dependents[f.result.step.name] {
+			if !f.result.satisfies(dependent) {
+				skip(dependent, f.result.step.name)
+				continue
+			}
+			remaining[dependent.name]--
+			if remaining[dependent.name] == 0 {
+				if _, ok := results[dependent.name]; !ok {
+					start(dependent)
+				}
+			}
+		}
+	}
+
+	ordered := make([]stepResult, 0, len(results))
+	for _, step := range steps {
+		if result, ok := results[step.name]; ok {
+			ordered = append(ordered, result)
+		}
+	}
+	return ordered
+}
+
+// mergeStepStatus merges the status changes made by a step on its own copy of
+// the HStreamDB, compared to the snapshot taken before the step ran, back into
+// the shared object.
+func mergeStepStatus(hdb, snapshot, updated *hapi.HStreamDB) {
+	if updated.ResourceVersion != snapshot.ResourceVersion {
+		hdb.ResourceVersion = updated.ResourceVersion
+	}
+
+	for _, condition := range updated.Status.Conditions {
+		if _, old := snapshot.GetCondition(condition.Type); old == nil || *old != condition {
+			index, _ := hdb.GetCondition(condition.Type)
+			if index == -1 {
+				hdb.Status.Conditions = append(hdb.Status.Conditions, condition)
+			} else {
+				hdb.Status.Conditions[index] = condition
+			}
+		}
+	}
+	for _, condition := range snapshot.Status.Conditions {
+		if index, _ := updated.GetCondition(condition.Type); index == -1 {
+			hdb.RemoveCondition(condition.Type)
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(snapshot.Status.HMeta, updated.Status.HMeta) {
+		hdb.Status.HMeta = updated.Status.HMeta
+	}
+}
+
+// recordStepResults records the outcome of each step into the status.
+func recordStepResults(hdb *hapi.HStreamDB, results []stepResult) {
+	hdb.Status.Reconciliation = make([]hapi.ReconcileStep, 0, len(results))
+	for _, result := range results {
+		step := hapi.ReconcileStep{
+			Name:    result.step.name,
+			Outcome: result.outcome,
+		}
+		if result.requeue != nil {
+			step.Message = result.requeue.message
+			if step.Message == "" && result.requeue.curError != nil {
+				step.Message = result.requeue.curError.Error()
+			}
+		}
+		hdb.Status.Reconciliation = append(hdb.Status.Reconciliation, step)
+	}
+}
+
+// updateReconcileErrorCondition sets the ReconcileError condition from the
+// failures of the steps, the reason is taken from the first failed step.
+func updateReconcileErrorCondition(hdb *hapi.HStreamDB, results []stepResult) {
+	var reason requeueReason
+	var messages []string
+	for _, result := range results {
+		if result.requeue == nil || !result.requeue.isFailure() {
+			continue
+		}
+		if reason == "" {
+			reason = result.requeue.effectiveReason()
+		}
+		message := result.requeue.message
+		if message == "" && result.requeue.curError != nil {
+			message = result.requeue.curError.Error()
+		}
+		messages = append(messages, fmt.Sprintf("%s: %s", result.step.name, message))
+	}
+
+	if reason == "" {
+		if _, c := hdb.GetCondition(hapi.ReconcileError); c != nil {
+			hdb.SetCondition(metav1.Condition{
+				Type:    hapi.ReconcileError,
+				Status:  metav1.ConditionFalse,
+				Reason:  hapi.ReasonReconcileSucceeded,
+				Message: "No error in the last reconciliation",
+			})
+		}
+		return
+	}
+
+	hdb.SetCondition(metav1.Condition{
+		Type:    hapi.ReconcileError,
+		Status:  metav1.ConditionTrue,
+		Reason:  string(reason),
+		Message: strings.Join(messages, "; "),
+	})
+}
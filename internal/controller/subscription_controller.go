@@ -0,0 +1,310 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"github.com/hstreamdb/hstream-operator/internal/admin"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// subscriptionFinalizer deletes the subscription from HStreamDB, it is
+	// only added to the Subscriptions with the Delete deletion policy.
+	subscriptionFinalizer = "apps.hstream.io/subscription"
+
+	// subscriptionResyncPeriod defines how often the consumers and backlog of
+	// a subscription are observed again.
+	subscriptionResyncPeriod = time.Minute
+
+	// subscriptionPendingDelay defines how often a subscription waiting for
+	// its stream, or for its consumers to be gone, is reconciled again.
+	subscriptionPendingDelay = 15 * time.Second
+)
+
+// errStreamNotFound is returned when the stream of a subscription does not exist.
+var errStreamNotFound = errors.New("stream not found")
+
+// SubscriptionReconciler reconciles a Subscription object
+type SubscriptionReconciler struct {
+	client.Client
+	Scheme              *runtime.Scheme
+	Recorder            record.EventRecorder
+	AdminClientProvider admin.AdminClientProvider
+}
+
+//+kubebuilder:rbac:groups=apps.hstream.io,resources=subscriptions,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.hstream.io,resources=subscriptions/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=apps.hstream.io,resources=subscriptions/finalizers,verbs=update
+
+// Reconcile creates the subscription of a Subscription in its HStreamDB, and
+// reports its consumers and backlog.
+func (r *SubscriptionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	sub := &hapi.Subscription{}
+	if err := r.Get(ctx, req.NamespacedName, sub); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !sub.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, sub)
+	}
+
+	if err := r.syncFinalizer(ctx, sub); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	hdbName := sub.Spec.HStreamDBRef.Name
+	hdb, err := getHStreamDB(ctx, r.Client, sub.Namespace, sub.Spec.HStreamDBRef)
+	if err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, r.updateStatus(ctx, sub, hapi.SubscriptionStatePending, metav1.ConditionFalse,
+			hapi.ReasonHStreamDBNotFound, fmt.Sprintf("HStreamDB %s not found", hdbName))
+	}
+	if !isHStreamDBReady(hdb) {
+		return ctrl.Result{}, r.updateStatus(ctx, sub, hapi.SubscriptionStatePending, metav1.ConditionFalse,
+			hapi.ReasonHStreamDBNotReady, fmt.Sprintf("HStreamDB %s is not ready", hdbName))
+	}
+
+	adminClient := admin.NewTypedAdminClient(r.AdminClientProvider.GetAdminClient(hdb))
+	observed, err := r.ensureSubscription(ctx, adminClient, sub)
+	if errors.Is(err, errStreamNotFound) {
+		// The stream may be created later, e.g. by a Stream.
+		return ctrl.Result{RequeueAfter: subscriptionPendingDelay},
+			r.updateStatus(ctx, sub, hapi.SubscriptionStatePending, metav1.ConditionFalse,
+				hapi.ReasonStreamNotFound, fmt.Sprintf("Stream %s not found in HStreamDB %s", sub.Spec.StreamName, hdbName))
+	}
+	if err != nil {
+		r.Recorder.Event(sub, corev1.EventTypeWarning, hapi.ReasonAdminCommandFailed, err.Error())
+		return ctrl.Result{}, utilerrors.NewAggregate([]error{err,
+			r.updateStatus(ctx, sub, hapi.SubscriptionStateFailed, metav1.ConditionFalse, hapi.ReasonAdminCommandFailed, err.Error()),
+		})
+	}
+
+	sub.Status.AckTimeoutSeconds = observed.AckTimeoutSeconds
+	sub.Status.MaxUnackedRecords = observed.MaxUnackedRecords
+	sub.Status.Consumers = observed.Consumers
+	sub.Status.Backlog = observed.Backlog
+
+	status, reason, message := metav1.ConditionTrue, hapi.ReasonSubscriptionCreated,
+		fmt.Sprintf("Subscription %s is created", observed.ID)
+	if diffs := subscriptionSettingsDiff(sub.Spec, observed); len(diffs) > 0 {
+		// The subscription is usable, but it is not the one declared by the spec.
+		status = metav1.ConditionFalse
+		reason = hapi.ReasonSubscriptionSettingsDiffer
+		message = fmt.Sprintf("Settings of subscription %s cannot be changed once created: %s",
+			observed.ID, strings.Join(diffs, ", "))
+		if old := meta.FindStatusCondition(sub.Status.Conditions, hapi.Ready); old == nil || old.Message != message {
+			r.Recorder.Event(sub, corev1.EventTypeWarning, reason, message)
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: subscriptionResyncPeriod},
+		r.updateStatus(ctx, sub, hapi.SubscriptionStateCreated, status, reason, message)
+}
+
+// ensureSubscription creates the subscription if it does not exist yet, and
+// returns the subscription observed in HStreamDB.
+func (r *SubscriptionReconciler) ensureSubscription(ctx context.Context, adminClient admin.TypedAdminClient,
+	sub *hapi.Subscription) (*admin.Subscription, error) {
+	id := sub.GetSubscriptionName()
+	observed, err := adminClient.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscription %s: %w", id, err)
+	}
+	if observed != nil {
+		return observed, nil
+	}
+
+	stream, err := adminClient.GetStream(ctx, sub.Spec.StreamName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stream %s: %w", sub.Spec.StreamName, err)
+	}
+	if stream == nil {
+		return nil, errStreamNotFound
+	}
+
+	if err = adminClient.CreateSubscription(ctx, id, admin.SubscriptionOptions{
+		StreamName:        sub.Spec.StreamName,
+		AckTimeoutSeconds: sub.Spec.AckTimeoutSeconds,
+		MaxUnackedRecords: sub.Spec.MaxUnackedRecords,
+		Offset:            string(sub.Spec.Offset),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create subscription %s: %w", id, err)
+	}
+	r.Recorder.Eventf(sub, corev1.EventTypeNormal, hapi.ReasonSubscriptionCreated,
+		"Created subscription %s of stream %s in HStreamDB %s", id, sub.Spec.StreamName, sub.Spec.HStreamDBRef.Name)
+
+	if observed, err = adminClient.GetSubscription(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to get subscription %s: %w", id, err)
+	}
+	if observed == nil {
+		return nil, fmt.Errorf("subscription %s not found after creation", id)
+	}
+	return observed, nil
+}
+
+// subscriptionSettingsDiff describes the settings of the subscription in
+// HStreamDB which differ from the spec.
+func subscriptionSettingsDiff(spec hapi.SubscriptionSpec, observed *admin.Subscription) (diffs []string) {
+	if spec.StreamName != observed.StreamName {
+		diffs = append(diffs, fmt.Sprintf("streamName is %s", observed.StreamName))
+	}
+	if spec.AckTimeoutSeconds != observed.AckTimeoutSeconds {
+		diffs = append(diffs, fmt.Sprintf("ackTimeoutSeconds is %d", observed.AckTimeoutSeconds))
+	}
+	if spec.MaxUnackedRecords != observed.MaxUnackedRecords {
+		diffs = append(diffs, fmt.Sprintf("maxUnackedRecords is %d", observed.MaxUnackedRecords))
+	}
+	return
+}
+
+// syncFinalizer makes sure the finalizer is only present with the Delete
+// deletion policy.
+func (r *SubscriptionReconciler) syncFinalizer(ctx context.Context, sub *hapi.Subscription) error {
+	var updated bool
+	if sub.Spec.DeletionPolicy == hapi.DeletionPolicyDelete {
+		updated = controllerutil.AddFinalizer(sub, subscriptionFinalizer)
+	} else {
+		updated = controllerutil.RemoveFinalizer(sub, subscriptionFinalizer)
+	}
+	if !updated {
+		return nil
+	}
+	return r.Update(ctx, sub)
+}
+
+// finalize deletes the subscription from HStreamDB according to the deletion
+// policy. A subscription with active consumers is only deleted if forced.
+func (r *SubscriptionReconciler) finalize(ctx context.Context, sub *hapi.Subscription) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(sub, subscriptionFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if sub.Spec.DeletionPolicy == hapi.DeletionPolicyDelete {
+		blocked, err := r.deleteSubscription(ctx, sub)
+		if err != nil {
+			r.Recorder.Event(sub, corev1.EventTypeWarning, hapi.ReasonAdminCommandFailed, err.Error())
+			return ctrl.Result{}, err
+		}
+		if blocked {
+			return ctrl.Result{RequeueAfter: subscriptionPendingDelay}, nil
+		}
+	}
+
+	controllerutil.RemoveFinalizer(sub, subscriptionFinalizer)
+	return ctrl.Result{}, r.Update(ctx, sub)
+}
+
+// deleteSubscription deletes the subscription from HStreamDB, it is blocked
+// while the subscription has active consumers and the deletion is not forced.
+func (r *SubscriptionReconciler) deleteSubscription(ctx context.Context, sub *hapi.Subscription) (blocked bool, err error) {
+	hdb, err := getHStreamDB(ctx, r.Client, sub.Namespace, sub.Spec.HStreamDBRef)
+	if err != nil {
+		// The subscription is gone together with the HStreamDB.
+		return false, client.IgnoreNotFound(err)
+	}
+	if !hdb.DeletionTimestamp.IsZero() {
+		return false, nil
+	}
+
+	id := sub.GetSubscriptionName()
+	adminClient := admin.NewTypedAdminClient(r.AdminClientProvider.GetAdminClient(hdb))
+	observed, err := adminClient.GetSubscription(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to get subscription %s: %w", id, err)
+	}
+	if observed == nil {
+		return false, nil
+	}
+
+	if observed.Consumers > 0 && !sub.Spec.ForceDelete {
+		message := fmt.Sprintf("Subscription %s still has %d active consumers, "+
+			"set spec.forceDelete to delete it anyway", id, observed.Consumers)
+		if old := meta.FindStatusCondition(sub.Status.Conditions, hapi.Ready); old == nil || old.Message != message {
+			r.Recorder.Event(sub, corev1.EventTypeWarning, hapi.ReasonActiveConsumers, message)
+		}
+		sub.Status.Consumers = observed.Consumers
+		sub.Status.Backlog = observed.Backlog
+		return true, r.updateStatus(ctx, sub, hapi.SubscriptionStateDeleting, metav1.ConditionFalse,
+			hapi.ReasonActiveConsumers, message)
+	}
+
+	if err = adminClient.DeleteSubscription(ctx, id, sub.Spec.ForceDelete); err != nil {
+		return false, fmt.Errorf("failed to delete subscription %s: %w", id, err)
+	}
+	r.Recorder.Eventf(sub, corev1.EventTypeNormal, hapi.ReasonSubscriptionDeleted,
+		"Deleted subscription %s from HStreamDB %s", id, hdb.Name)
+	return false, nil
+}
+
+func (r *SubscriptionReconciler) updateStatus(ctx context.Context, sub *hapi.Subscription, state hapi.SubscriptionState,
+	status metav1.ConditionStatus, reason, message string) error {
+	sub.Status.State = state
+	sub.Status.ObservedGeneration = sub.Generation
+	meta.SetStatusCondition(&sub.Status.Conditions, metav1.Condition{
+		Type:               hapi.Ready,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: sub.Generation,
+	})
+	return r.Status().Update(ctx, sub)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SubscriptionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.AdminClientProvider = instrumentedAdminClientProvider{r.AdminClientProvider}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &hapi.Subscription{}, hstreamDBRefField,
+		func(obj client.Object) []string {
+			return []string{hstreamDBRefKey(obj.GetNamespace(), obj.(*hapi.Subscription).Spec.HStreamDBRef)}
+		}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		// The consumers and backlog written to the status change on every
+		// reconciliation, they are observed again by the periodic resync
+		// rather than by the status updates.
+		For(&hapi.Subscription{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Create the subscriptions once their HStreamDB becomes ready.
+		Watches(&source.Kind{Type: &hapi.HStreamDB{}},
+			handler.EnqueueRequestsFromMapFunc(requestsForHStreamDB(mgr.GetClient(), &hapi.SubscriptionList{})),
+			builder.WithPredicates(hstreamDBReadinessChanged)).
+		Complete(r)
+}
@@ -0,0 +1,158 @@
+package controller
+
+import (
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"github.com/hstreamdb/hstream-operator/internal/admin"
+	"github.com/hstreamdb/hstream-operator/mock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("controller/subscription", Ordered, func() {
+	const namespace = "subscription-test"
+	var hdb *hapi.HStreamDB
+	var server *fakeServerAdminClient
+	var reconciler *SubscriptionReconciler
+
+	newSubscription := func(name string) *hapi.Subscription {
+		return &hapi.Subscription{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: hapi.SubscriptionSpec{
+				HStreamDBRef:      hapi.HStreamDBReference{Name: hdb.Name},
+				StreamName:        "orders",
+				AckTimeoutSeconds: 60,
+				MaxUnackedRecords: 10000,
+				Offset:            hapi.SubscriptionOffsetEarliest,
+			},
+		}
+	}
+
+	reconcileSubscription := func(sub *hapi.Subscription) (ctrl.Result, error) {
+		res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(sub)})
+		if getErr := k8sClient.Get(ctx, client.ObjectKeyFromObject(sub), sub); getErr != nil && !k8sErrors.IsNotFound(getErr) {
+			Expect(getErr).To(Succeed())
+		}
+		return res, err
+	}
+
+	BeforeAll(func() {
+		Expect(k8sClient.Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: namespace},
+		})).To(Succeed())
+
+		hdb = mock.CreateDefaultCR()
+		hdb.Name = "subscription-hdb"
+		hdb.Namespace = namespace
+		Expect(k8sClient.Create(ctx, hdb)).To(Succeed())
+		for _, condition := range []string{hapi.HServerReady, hapi.AdminServerReady} {
+			hdb.SetCondition(metav1.Condition{Type: condition, Status: metav1.ConditionTrue, Reason: "test"})
+		}
+		Expect(k8sClient.Status().Update(ctx, hdb)).To(Succeed())
+	})
+
+	BeforeEach(func() {
+		server = newFakeServerAdminClient()
+		reconciler = &SubscriptionReconciler{
+			Client:              k8sClient,
+			Scheme:              k8sClient.Scheme(),
+			Recorder:            record.NewFakeRecorder(100),
+			AdminClientProvider: fakeAdminClientProvider{client: server},
+		}
+	})
+
+	It("should wait for the stream", func() {
+		sub := newSubscription("pending")
+		Expect(k8sClient.Create(ctx, sub)).To(Succeed())
+
+		res, err := reconcileSubscription(sub)
+		Expect(err).To(Succeed())
+		Expect(res.RequeueAfter).To(Equal(subscriptionPendingDelay))
+		Expect(sub.Status.State).To(Equal(hapi.SubscriptionStatePending))
+		Expect(sub.Status.Conditions).To(ContainElement(And(
+			HaveField("Type", hapi.Ready),
+			HaveField("Status", metav1.ConditionFalse),
+			HaveField("Reason", hapi.ReasonStreamNotFound),
+		)))
+	})
+
+	It("should create the subscription and report its consumers", func() {
+		server.streams["orders"] = admin.Stream{Name: "orders"}
+		sub := newSubscription("billing")
+		sub.Spec.SubscriptionName = "orders-billing"
+		Expect(k8sClient.Create(ctx, sub)).To(Succeed())
+
+		res, err := reconcileSubscription(sub)
+		Expect(err).To(Succeed())
+		Expect(res.RequeueAfter).To(Equal(subscriptionResyncPeriod))
+		Expect(server.commands).To(ContainElement(
+			"sub create orders-billing --stream orders --ack-timeout 60 --max-unacked-records 10000 --offset earliest"))
+		Expect(sub.Status.State).To(Equal(hapi.SubscriptionStateCreated))
+		Expect(sub.Status.Conditions).To(ContainElement(HaveField("Reason", hapi.ReasonSubscriptionCreated)))
+
+		By("observing the consumers and backlog")
+		observed := server.subscriptions["orders-billing"]
+		observed.Consumers, observed.Backlog = 2, 1520
+		server.subscriptions["orders-billing"] = observed
+
+		_, err = reconcileSubscription(sub)
+		Expect(err).To(Succeed())
+		Expect(sub.Status.Consumers).To(BeEquivalentTo(2))
+		Expect(sub.Status.Backlog).To(BeEquivalentTo(1520))
+	})
+
+	It("should not be ready while the settings differ from the spec", func() {
+		server.streams["orders"] = admin.Stream{Name: "orders"}
+		server.subscriptions["legacy"] = admin.Subscription{
+			ID: "legacy", StreamName: "orders", AckTimeoutSeconds: 30, MaxUnackedRecords: 10000,
+		}
+		sub := newSubscription("legacy")
+		Expect(k8sClient.Create(ctx, sub)).To(Succeed())
+
+		_, err := reconcileSubscription(sub)
+		Expect(err).To(Succeed())
+		Expect(sub.Status.State).To(Equal(hapi.SubscriptionStateCreated))
+		Expect(sub.Status.Conditions).To(ContainElement(And(
+			HaveField("Type", hapi.Ready),
+			HaveField("Status", metav1.ConditionFalse),
+			HaveField("Reason", hapi.ReasonSubscriptionSettingsDiffer),
+			HaveField("Message", ContainSubstring("ackTimeoutSeconds is 30")),
+		)))
+	})
+
+	It("should not delete a subscription with active consumers unless forced", func() {
+		server.streams["orders"] = admin.Stream{Name: "orders"}
+		sub := newSubscription("audit")
+		sub.Spec.DeletionPolicy = hapi.DeletionPolicyDelete
+		Expect(k8sClient.Create(ctx, sub)).To(Succeed())
+		_, err := reconcileSubscription(sub)
+		Expect(err).To(Succeed())
+		Expect(sub.Finalizers).To(ContainElement(subscriptionFinalizer))
+
+		observed := server.subscriptions["audit"]
+		observed.Consumers = 1
+		server.subscriptions["audit"] = observed
+
+		Expect(k8sClient.Delete(ctx, sub)).To(Succeed())
+		res, err := reconcileSubscription(sub)
+		Expect(err).To(Succeed())
+		Expect(res.RequeueAfter).To(Equal(subscriptionPendingDelay))
+		Expect(server.subscriptions).To(HaveKey("audit"))
+		Expect(sub.Status.State).To(Equal(hapi.SubscriptionStateDeleting))
+		Expect(sub.Status.Conditions).To(ContainElement(HaveField("Reason", hapi.ReasonActiveConsumers)))
+
+		By("forcing the deletion")
+		sub.Spec.ForceDelete = true
+		Expect(k8sClient.Update(ctx, sub)).To(Succeed())
+		_, err = reconcileSubscription(sub)
+		Expect(err).To(Succeed())
+		Expect(server.commands).To(ContainElement("sub delete audit --force"))
+		Expect(server.subscriptions).NotTo(HaveKey("audit"))
+		Expect(k8sErrors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(sub), sub))).To(BeTrue())
+	})
+})
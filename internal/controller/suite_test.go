@@ -32,6 +32,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
@@ -101,7 +102,8 @@ var _ = BeforeSuite(func() {
 	Expect(k8sClient).NotTo(BeNil())
 
 	k8sManager, err := ctrl.NewManager(cfg, ctrl.Options{
-		Scheme: scheme.Scheme,
+		Scheme:                scheme.Scheme,
+		ClientDisableCacheFor: []client.Object{&corev1.Secret{}},
 	})
 	Expect(err).NotTo(HaveOccurred())
 
@@ -118,8 +120,9 @@ var _ = BeforeSuite(func() {
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 	err = (&ConnectorReconciler{
-		Client: k8sManager.GetClient(),
-		Scheme: k8sManager.GetScheme(),
+		Client:   k8sManager.GetClient(),
+		Scheme:   k8sManager.GetScheme(),
+		Recorder: k8sManager.GetEventRecorderFor("connector-controller"),
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -138,11 +141,14 @@ var _ = AfterSuite(func() {
 })
 
 func createTestHStreamDBReconciler() *HStreamDBReconciler {
+	adminClientProvider, err := admin.NewAdminClientProvider(cfg, logf.Log.WithName("HStreamDB Controller"))
+	Expect(err).NotTo(HaveOccurred())
+
 	return &HStreamDBReconciler{
 		Client:              k8sClient,
 		Scheme:              k8sClient.Scheme(),
 		Recorder:            record.NewFakeRecorder(100),
-		AdminClientProvider: admin.NewAdminClientProvider(cfg, logf.Log.WithName("HStreamDB Controller")),
+		AdminClientProvider: adminClientProvider,
 	}
 }
 
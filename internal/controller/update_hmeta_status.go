@@ -25,16 +25,13 @@ func (u updateHMetaStatus) reconcile(ctx context.Context, r *HStreamDBReconciler
 		}
 		if err = checkPodRunningStatus(ctx, r.Client, hdb, sts); err != nil {
 			// print message only to log, wait for reconciling after several second
-			return &requeue{message: err.Error(), delay: time.Second}
+			return u.setNotReady(ctx, r, hdb, hapi.ReasonReplicasNotReady, err.Error(), reasonWaitingForPods)
 		}
 	}
 
 	var cluster admin.HMetaStatus
-	if cluster, err = r.AdminClientProvider.GetAdminClient(hdb).GetHMetaStatus(); err != nil {
-		return &requeue{message: err.Error(), delay: time.Second}
-	}
-	if !cluster.IsAllReady() {
-		return &requeue{message: "wait for HMeta cluster to be ready", delay: time.Second}
+	if cluster, err = r.AdminClientProvider.GetAdminClient(hdb).GetHMetaStatus(ctx); err != nil {
+		return u.setNotReady(ctx, r, hdb, hapi.ReasonNodesUnreachable, err.Error(), reasonHMetaUnreachable)
 	}
 
 	hdb.Status.HMeta.Nodes = make([]hapi.HMetaNode, 0, len(cluster.Nodes))
@@ -46,14 +43,33 @@ func (u updateHMetaStatus) reconcile(ctx context.Context, r *HStreamDBReconciler
 			Error:     node.Error,
 		})
 	}
+	if !cluster.IsAllReady() {
+		return u.setNotReady(ctx, r, hdb, hapi.ReasonNodesUnreachable, "wait for HMeta cluster to be ready", reasonHMetaNotReady)
+	}
+
 	hdb.SetCondition(metav1.Condition{
 		Type:    hapi.HMetaReady,
 		Status:  metav1.ConditionTrue,
-		Reason:  hapi.HMetaReady,
+		Reason:  hapi.ReasonAllNodesReachable,
 		Message: "HMeta is ready",
 	})
 	if err := r.Status().Update(ctx, hdb); err != nil {
-		return &requeue{curError: fmt.Errorf("update HMeta status failed: %w", err)}
+		return &requeue{curError: fmt.Errorf("update HMeta status failed: %w", err), reason: reasonStatusUpdateFailed}
 	}
 	return nil
 }
+
+// setNotReady marks HMeta as not ready and waits for the next check.
+func (u updateHMetaStatus) setNotReady(ctx context.Context, r *HStreamDBReconciler, hdb *hapi.HStreamDB,
+	reason, message string, requeueReason requeueReason) *requeue {
+	hdb.SetCondition(metav1.Condition{
+		Type:    hapi.HMetaReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+	if err := r.Status().Update(ctx, hdb); err != nil {
+		return &requeue{curError: fmt.Errorf("update HMeta status failed: %w", err), reason: reasonStatusUpdateFailed}
+	}
+	return &requeue{message: message, delay: time.Second, reason: requeueReason}
+}
@@ -3,10 +3,13 @@ package controller
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
 	"github.com/hstreamdb/hstream-operator/internal"
+	"github.com/hstreamdb/hstream-operator/internal/admin"
 	appsv1 "k8s.io/api/apps/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -21,54 +24,126 @@ func (u updateStatus) reconcile(ctx context.Context, r *HStreamDBReconciler, hdb
 	if err := u.checkAllReady(ctx, r, hdb); err != nil {
 		return &requeue{curError: err}
 	}
+	recordClusterMetrics(hdb)
 
 	if err := r.Status().Update(ctx, hdb); err != nil {
-		return &requeue{curError: err}
+		return &requeue{curError: err, reason: reasonStatusUpdateFailed}
 	}
 
 	if !hdb.IsConditionTrue(hapi.Ready) {
-		return &requeue{message: "HStreamDB is not ready", delayedRequeue: true}
+		return &requeue{message: "HStreamDB is not ready", delayedRequeue: true, reason: reasonClusterNotReady}
 	}
 
 	return nil
 }
 
+// checkComponentsReady evaluates the readiness of every component from live
+// signals, so that a component which was ready before can be marked as
+// degraded again.
 func (u updateStatus) checkComponentsReady(ctx context.Context, r *HStreamDBReconciler, hdb *hapi.HStreamDB) error {
-	componentsMap := make(map[hapi.ComponentType]string)
+	statefulSets := []componentWorkload{
+		{hapi.HStoreReady, hapi.ComponentTypeHStore, hdb.Spec.HStore.Replicas},
+		{hapi.HServerReady, hapi.ComponentTypeHServer, hdb.Spec.HServer.Replicas},
+	}
+	bootstrappedConditions := map[string]string{
+		hapi.HStoreReady:  hapi.HStoreBootstrapped,
+		hapi.HServerReady: hapi.HServerBootstrapped,
+	}
+	for _, w := range statefulSets {
+		bootstrapped := bootstrappedConditions[w.condition]
+		if !isBootstrapped(hdb, bootstrapped, w.condition) {
+			hdb.SetCondition(metav1.Condition{
+				Type:    w.condition,
+				Status:  metav1.ConditionFalse,
+				Reason:  hapi.ReasonNotBootstrapped,
+				Message: fmt.Sprintf("%s has not been bootstrapped", w.component),
+			})
+			continue
+		}
 
+		// Record the marker for clusters bootstrapped by earlier operator
+		// versions before the legacy condition is overwritten below.
+		if !hdb.IsConditionTrue(bootstrapped) {
+			hdb.SetCondition(metav1.Condition{
+				Type:    bootstrapped,
+				Status:  metav1.ConditionTrue,
+				Reason:  hapi.ReasonBootstrapped,
+				Message: fmt.Sprintf("%s has been bootstrapped", w.component),
+			})
+		}
+
+		sts := &appsv1.StatefulSet{
+			ObjectMeta: internal.GetObjectMetadata(hdb, nil, w.component),
+		}
+		condition, err := w.checkReady(ctx, r.Client, sts)
+		if err != nil {
+			return err
+		}
+		// The condition is set once with the result of both checks, so that
+		// its transition time is kept while HServer stays not ready.
+		if w.condition == hapi.HServerReady && condition.Status == metav1.ConditionTrue {
+			condition = u.checkServerNodes(ctx, r, hdb, condition)
+		}
+		hdb.SetCondition(condition)
+	}
+
+	deployments := []componentWorkload{
+		{hapi.AdminServerReady, hapi.ComponentTypeAdminServer, hdb.Spec.AdminServer.Replicas},
+	}
 	if hdb.Spec.Console != nil {
-		componentsMap[hapi.ComponentTypeConsole] = hapi.ConsoleReady
+		deployments = append(deployments, componentWorkload{hapi.ConsoleReady, hapi.ComponentTypeConsole, hdb.Spec.Console.Replicas})
+	} else {
+		hdb.RemoveCondition(hapi.ConsoleReady)
 	}
 	if hdb.Spec.Gateway != nil {
-		componentsMap[hapi.ComponentTypeGateway] = hapi.GatewayReady
+		deployments = append(deployments, componentWorkload{hapi.GatewayReady, hapi.ComponentTypeGateway, hdb.Spec.Gateway.Replicas})
+	} else {
+		hdb.RemoveCondition(hapi.GatewayReady)
 	}
-
-	for component, condition := range componentsMap {
-		deploy := &appsv1.Deployment{}
-		deploy.ObjectMeta = internal.GetObjectMetadata(hdb, nil, component)
-		err := r.Get(ctx, client.ObjectKeyFromObject(deploy), deploy)
-		if err != nil {
+	for _, w := range deployments {
+		deploy := &appsv1.Deployment{
+			ObjectMeta: internal.GetObjectMetadata(hdb, nil, w.component),
+		}
+		if err := w.setCondition(ctx, r.Client, hdb, deploy); err != nil {
 			return err
 		}
-		hdb.SetCondition(metav1.Condition{
-			Type:    condition,
-			Status:  metav1.ConditionTrue,
-			Reason:  condition,
-			Message: fmt.Sprintf("%s is ready", component),
-		})
 	}
 	return nil
 }
 
-func (u updateStatus) checkAllReady(ctx context.Context, r *HStreamDBReconciler, hdb *hapi.HStreamDB) error {
+// checkServerNodes returns the condition of HServer whose replicas are ready,
+// or marks HServer as not ready unless the admin server reports that its nodes
+// are running.
+func (u updateStatus) checkServerNodes(ctx context.Context, r *HStreamDBReconciler, hdb *hapi.HStreamDB, ready metav1.Condition) metav1.Condition {
 	condition := metav1.Condition{
-		Type:   hapi.Ready,
+		Type:   hapi.HServerReady,
 		Status: metav1.ConditionFalse,
-		Reason: "AllComponentsNotReady",
+	}
+	nodes, err := admin.NewTypedAdminClient(r.AdminClientProvider.GetAdminClient(hdb)).ListServerNodes(ctx)
+	if err != nil {
+		condition.Reason = hapi.ReasonAdminCommandFailed
+		condition.Message = fmt.Sprintf("fail to get the status of HServer nodes: %s", err.Error())
+		return condition
 	}
 
+	var running int32
+	for _, node := range nodes {
+		if node.IsRunning() {
+			running++
+		}
+	}
+	if running < hdb.Spec.HServer.Replicas {
+		condition.Reason = hapi.ReasonNodesNotRunning
+		condition.Message = fmt.Sprintf("%d/%d nodes of HServer are running", running, hdb.Spec.HServer.Replicas)
+		return condition
+	}
+	return ready
+}
+
+func (u updateStatus) checkAllReady(ctx context.Context, r *HStreamDBReconciler, hdb *hapi.HStreamDB) error {
 	conditionList := []string{
 		hapi.HMetaReady,
+		hapi.AdminServerReady,
 		hapi.HStoreReady,
 		hapi.HServerReady,
 	}
@@ -81,20 +156,105 @@ func (u updateStatus) checkAllReady(ctx context.Context, r *HStreamDBReconciler,
 		conditionList = append(conditionList, hapi.GatewayReady)
 	}
 
+	var notReady []string
 	for _, t := range conditionList {
 		if !hdb.IsConditionTrue(t) {
-			hdb.SetCondition(condition)
-
-			return nil
+			notReady = append(notReady, strings.TrimSuffix(t, "Ready"))
 		}
 	}
 
+	if len(notReady) > 0 {
+		hdb.SetCondition(metav1.Condition{
+			Type:    hapi.Ready,
+			Status:  metav1.ConditionFalse,
+			Reason:  hapi.ReasonComponentsNotReady,
+			Message: fmt.Sprintf("Components not ready: %s", strings.Join(notReady, ", ")),
+		})
+		return nil
+	}
+
 	// Mark all components are ready in condition.
-	condition.Status = metav1.ConditionTrue
-	condition.Reason = "AllComponentsReady"
-	condition.Message = "All components are ready"
+	hdb.SetCondition(metav1.Condition{
+		Type:    hapi.Ready,
+		Status:  metav1.ConditionTrue,
+		Reason:  hapi.ReasonAllComponentsReady,
+		Message: "All components are ready",
+	})
 
-	hdb.SetCondition(condition)
+	return nil
+}
+
+// componentWorkload binds a readiness condition to the workload of a component.
+type componentWorkload struct {
+	condition string
+	component hapi.ComponentType
+	replicas  int32
+}
 
+// setCondition sets the readiness condition of the component according to the
+// ready replicas of its StatefulSet or Deployment.
+func (w componentWorkload) setCondition(ctx context.Context, c client.Client, hdb *hapi.HStreamDB, obj client.Object) error {
+	condition, err := w.checkReady(ctx, c, obj)
+	if err != nil {
+		return err
+	}
+	hdb.SetCondition(condition)
 	return nil
 }
+
+// checkReady returns the readiness condition of the component according to the
+// ready replicas of its StatefulSet or Deployment.
+func (w componentWorkload) checkReady(ctx context.Context, c client.Client, obj client.Object) (metav1.Condition, error) {
+	condition, err := checkWorkloadReady(ctx, c, obj, w.replicas)
+	condition.Type = w.condition
+	return condition, err
+}
+
+// checkWorkloadReady returns a condition, without type, that describes whether
+// the ready replicas of a StatefulSet or Deployment reach the desired replicas.
+func checkWorkloadReady(ctx context.Context, c client.Client, obj client.Object, desired int32) (metav1.Condition, error) {
+	condition := metav1.Condition{
+		Status: metav1.ConditionFalse,
+	}
+
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return condition, err
+		}
+		condition.Reason = hapi.ReasonWorkloadNotFound
+		condition.Message = fmt.Sprintf("%s does not exist", obj.GetName())
+		return condition, nil
+	}
+
+	var ready int32
+	switch workload := obj.(type) {
+	case *appsv1.StatefulSet:
+		ready = workload.Status.ReadyReplicas
+	case *appsv1.Deployment:
+		ready = workload.Status.ReadyReplicas
+	default:
+		return condition, fmt.Errorf("%s is neither StatefulSet nor Deployment", obj.GetName())
+	}
+
+	condition.Message = fmt.Sprintf("%d/%d replicas of %s are ready", ready, desired, obj.GetName())
+	if ready < desired || desired == 0 {
+		condition.Reason = hapi.ReasonReplicasNotReady
+		return condition, nil
+	}
+
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = hapi.ReasonAllReplicasReady
+	return condition, nil
+}
+
+// isBootstrapped reports whether the given bootstrapped condition is true.
+// Clusters bootstrapped by earlier operator versions only carry the legacy
+// readiness condition, whose reason was set to the condition type itself.
+func isBootstrapped(hdb *hapi.HStreamDB, bootstrapped, legacy string) bool {
+	if hdb.IsConditionTrue(bootstrapped) {
+		return true
+	}
+
+	_, condition := hdb.GetCondition(legacy)
+	return condition != nil && condition.Status == metav1.ConditionTrue && condition.Reason == legacy
+}
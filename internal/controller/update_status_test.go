@@ -2,9 +2,12 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
 	"github.com/hstreamdb/hstream-operator/internal"
+	"github.com/hstreamdb/hstream-operator/internal/admin"
 	"github.com/hstreamdb/hstream-operator/mock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -36,7 +39,7 @@ var _ = Describe("UpdateStatus", func() {
 	})
 
 	It("check conditions", func() {
-		Expect(updateStatus.reconcile(ctx, clusterReconciler, hdb)).To(Equal(&requeue{message: "HStreamDB is not ready", delayedRequeue: true}))
+		Expect(updateStatus.reconcile(ctx, clusterReconciler, hdb)).To(Equal(&requeue{message: "HStreamDB is not ready", delayedRequeue: true, reason: reasonClusterNotReady}))
 		storeHdb := &hapi.HStreamDB{}
 		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(hdb), storeHdb)).To(BeNil())
 		Expect(storeHdb.Status.Conditions).To(ContainElement(
@@ -48,7 +51,24 @@ var _ = Describe("UpdateStatus", func() {
 	})
 
 	When("all components have been ready", func() {
+		var server *fakeServerAdminClient
+		var provider admin.AdminClientProvider
+
+		BeforeEach(func() {
+			server = newFakeServerAdminClient()
+			for i := 0; i < int(hdb.Spec.HServer.Replicas); i++ {
+				server.serverNodes[100+i] = "Running"
+			}
+			provider = clusterReconciler.AdminClientProvider
+			clusterReconciler.AdminClientProvider = fakeAdminClientProvider{client: server}
+		})
+
+		AfterEach(func() {
+			clusterReconciler.AdminClientProvider = provider
+		})
+
 		JustBeforeEach(func() {
+			prepareWorkloadsReady(ctx, hdb)
 			hdb.Status.HMeta.Nodes = []hapi.HMetaNode{}
 			hdb.SetCondition(metav1.Condition{
 				Type:    hapi.HMetaReady,
@@ -57,25 +77,13 @@ var _ = Describe("UpdateStatus", func() {
 				Message: "test",
 			})
 			hdb.SetCondition(metav1.Condition{
-				Type:    hapi.HStoreReady,
+				Type:    hapi.HStoreBootstrapped,
 				Status:  metav1.ConditionTrue,
 				Reason:  "test",
 				Message: "test",
 			})
 			hdb.SetCondition(metav1.Condition{
-				Type:    hapi.HServerReady,
-				Status:  metav1.ConditionTrue,
-				Reason:  "test",
-				Message: "test",
-			})
-			hdb.SetCondition(metav1.Condition{
-				Type:    hapi.GatewayReady,
-				Status:  metav1.ConditionTrue,
-				Reason:  "test",
-				Message: "test",
-			})
-			hdb.SetCondition(metav1.Condition{
-				Type:    hapi.ConsoleReady,
+				Type:    hapi.HServerBootstrapped,
 				Status:  metav1.ConditionTrue,
 				Reason:  "test",
 				Message: "test",
@@ -93,6 +101,64 @@ var _ = Describe("UpdateStatus", func() {
 				),
 			))
 		})
+
+		It("should mark the cluster degraded once replicas are not ready", func() {
+			Expect(updateStatus.reconcile(ctx, clusterReconciler, hdb)).To(BeNil())
+			Expect(hdb.IsConditionTrue(hapi.HStoreReady)).To(BeTrue())
+
+			hStore := &appsv1.StatefulSet{
+				ObjectMeta: internal.GetObjectMetadata(hdb, nil, hapi.ComponentTypeHStore),
+			}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(hStore), hStore)).To(BeNil())
+			hStore.Status.ReadyReplicas = 1
+			Expect(k8sClient.Status().Update(ctx, hStore)).To(BeNil())
+
+			Expect(updateStatus.reconcile(ctx, clusterReconciler, hdb)).To(Equal(&requeue{message: "HStreamDB is not ready", delayedRequeue: true, reason: reasonClusterNotReady}))
+			_, condition := hdb.GetCondition(hapi.HStoreReady)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(hapi.ReasonReplicasNotReady))
+			Expect(hdb.IsConditionTrue(hapi.HStoreBootstrapped)).To(BeTrue())
+			Expect(hdb.IsConditionTrue(hapi.Ready)).To(BeFalse())
+		})
+
+		It("should mark HServer not ready unless the admin server reports its nodes running", func() {
+			server.serverNodes[100] = "Starting"
+
+			Expect(updateStatus.reconcile(ctx, clusterReconciler, hdb)).NotTo(BeNil())
+			_, condition := hdb.GetCondition(hapi.HServerReady)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(hapi.ReasonNodesNotRunning))
+			Expect(hdb.IsConditionTrue(hapi.Ready)).To(BeFalse())
+
+			By("keeping the transition time while HServer stays not ready")
+			transitionTime := metav1.NewTime(time.Now().Add(-time.Hour)).Rfc3339Copy()
+			index, _ := hdb.GetCondition(hapi.HServerReady)
+			hdb.Status.Conditions[index].LastTransitionTime = transitionTime
+			Expect(updateStatus.reconcile(ctx, clusterReconciler, hdb)).NotTo(BeNil())
+			_, condition = hdb.GetCondition(hapi.HServerReady)
+			Expect(condition.LastTransitionTime).To(Equal(transitionTime))
+
+			By("reporting the failure of the admin command")
+			server.err = fmt.Errorf("connection refused")
+			Expect(updateStatus.reconcile(ctx, clusterReconciler, hdb)).NotTo(BeNil())
+			_, condition = hdb.GetCondition(hapi.HServerReady)
+			Expect(condition.Reason).To(Equal(hapi.ReasonAdminCommandFailed))
+			Expect(condition.Message).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	It("should keep clusters bootstrapped by previous versions bootstrapped", func() {
+		hdb.SetCondition(metav1.Condition{
+			Type:   hapi.HStoreReady,
+			Status: metav1.ConditionTrue,
+			Reason: hapi.HStoreReady,
+		})
+
+		_ = updateStatus.reconcile(ctx, clusterReconciler, hdb)
+		Expect(hdb.IsConditionTrue(hapi.HStoreBootstrapped)).To(BeTrue())
+		Expect(hdb.IsConditionTrue(hapi.HServerBootstrapped)).To(BeFalse())
 	})
 })
 
@@ -103,7 +169,7 @@ func prepareGatewayReady(ctx context.Context, hdb *hapi.HStreamDB) {
 	hdb.Spec.Gateway.Image = "hstreamdb/hstream-gateway:latest"
 	hdb.Spec.Gateway.Replicas = 1
 	hdb.SetCondition(metav1.Condition{
-		Type:    hapi.HServerReady,
+		Type:    hapi.HServerBootstrapped,
 		Status:  metav1.ConditionTrue,
 		Reason:  "test",
 		Message: "test",
@@ -135,3 +201,20 @@ func prepareConsoleReady(ctx context.Context, hdb *hapi.HStreamDB) {
 	console.Status.ReadyReplicas = 1
 	Expect(k8sClient.Status().Update(ctx, console)).To(BeNil())
 }
+
+func prepareWorkloadsReady(ctx context.Context, hdb *hapi.HStreamDB) {
+	hStore := addHStore{}.getSts(hdb, hdb.Spec.Config.NShards)
+	hServer := addHServer{}.getSts(hdb)
+	for _, sts := range []*appsv1.StatefulSet{&hStore, &hServer} {
+		Expect(k8sClient.Create(ctx, sts)).To(BeNil())
+		sts.Status.Replicas = *sts.Spec.Replicas
+		sts.Status.ReadyReplicas = *sts.Spec.Replicas
+		Expect(k8sClient.Status().Update(ctx, sts)).To(BeNil())
+	}
+
+	adminServer := addAdminServer{}.getDeployment(hdb)
+	Expect(k8sClient.Create(ctx, &adminServer)).To(BeNil())
+	adminServer.Status.Replicas = 1
+	adminServer.Status.ReadyReplicas = 1
+	Expect(k8sClient.Status().Update(ctx, &adminServer)).To(BeNil())
+}
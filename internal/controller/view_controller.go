@@ -0,0 +1,245 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"github.com/hstreamdb/hstream-operator/internal/admin"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// viewFinalizer removes the view from HStreamDB, it is only added to the
+// Views with the Delete deletion policy.
+const viewFinalizer = "apps.hstream.io/view"
+
+// ViewReconciler reconciles a View object
+type ViewReconciler struct {
+	client.Client
+	Scheme              *runtime.Scheme
+	Recorder            record.EventRecorder
+	AdminClientProvider admin.AdminClientProvider
+}
+
+//+kubebuilder:rbac:groups=apps.hstream.io,resources=views,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.hstream.io,resources=views/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=apps.hstream.io,resources=views/finalizers,verbs=update
+
+// Reconcile creates a View in its HStreamDB, recreates the view once the
+// statement changes and restarts the query materializing it once aborted.
+func (r *ViewReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	view := &hapi.View{}
+	if err := r.Get(ctx, req.NamespacedName, view); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !view.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, view)
+	}
+
+	if err := r.syncFinalizer(ctx, view); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	hdbName := view.Spec.HStreamDBRef.Name
+	hdb, err := getHStreamDB(ctx, r.Client, view.Namespace, view.Spec.HStreamDBRef)
+	if err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, r.updateStatus(ctx, view, hapi.SQLStatePending, metav1.Condition{
+			Status: metav1.ConditionFalse, Reason: hapi.ReasonHStreamDBNotFound,
+			Message: fmt.Sprintf("HStreamDB %s not found", hdbName),
+		})
+	}
+	if !isHStreamDBReady(hdb) {
+		return ctrl.Result{}, r.updateStatus(ctx, view, hapi.SQLStatePending, metav1.Condition{
+			Status: metav1.ConditionFalse, Reason: hapi.ReasonHStreamDBNotReady,
+			Message: fmt.Sprintf("HStreamDB %s is not ready", hdbName),
+		})
+	}
+
+	adminClient := admin.NewTypedAdminClient(r.AdminClientProvider.GetAdminClient(hdb))
+	observed, err := r.ensureView(ctx, adminClient, view)
+	if err != nil {
+		r.Recorder.Event(view, corev1.EventTypeWarning, hapi.ReasonAdminCommandFailed, err.Error())
+		return ctrl.Result{}, utilerrors.NewAggregate([]error{err,
+			r.updateStatus(ctx, view, hapi.SQLStateFailed, metav1.Condition{
+				Status: metav1.ConditionFalse, Reason: hapi.ReasonAdminCommandFailed, Message: err.Error(),
+			}),
+		})
+	}
+
+	query := observed.Query()
+	condition := observeSQLTask(&view.Status.SQLTaskStatus, &query)
+	return ctrl.Result{RequeueAfter: sqlResyncPeriod}, r.updateStatus(ctx, view, hapi.SQLStateCreated, condition)
+}
+
+// ensureView creates the view unless it exists with the same statement, and
+// returns the view observed in HStreamDB.
+func (r *ViewReconciler) ensureView(ctx context.Context, adminClient admin.TypedAdminClient, view *hapi.View) (*admin.View, error) {
+	name := view.GetViewName()
+	hash := hashSQL(view.Spec.SQL)
+
+	observed, err := adminClient.GetView(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get view %s: %w", name, err)
+	}
+	// A view created without recording the hash of its statement is adopted
+	// if it materializes the same statement.
+	if observed != nil && view.Status.SQLHash == "" && observed.HasSQL(view.Spec.SQL) {
+		view.Status.SQLHash = hash
+	}
+
+	if observed != nil && view.Status.SQLHash != hash {
+		if err = adminClient.DeleteView(ctx, name); err != nil {
+			return nil, fmt.Errorf("failed to delete view %s: %w", name, err)
+		}
+		r.Recorder.Eventf(view, corev1.EventTypeNormal, hapi.ReasonViewRecreated,
+			"Removed view %s since the statement changed", name)
+		observed = nil
+	}
+
+	if observed == nil {
+		if err = adminClient.CreateView(ctx, name, view.Spec.SQL); err != nil {
+			return nil, fmt.Errorf("failed to create view %s: %w", name, err)
+		}
+		view.Status.SQLHash = hash
+		r.Recorder.Eventf(view, corev1.EventTypeNormal, hapi.ReasonViewCreated,
+			"Created view %s in HStreamDB %s", name, view.Spec.HStreamDBRef.Name)
+	} else if query := observed.Query(); query.IsAborted() {
+		if err = adminClient.ResumeQuery(ctx, query.ID); err != nil {
+			return nil, fmt.Errorf("failed to restart query %s of view %s: %w", query.ID, name, err)
+		}
+		r.Recorder.Eventf(view, corev1.EventTypeNormal, hapi.ReasonQueryRestarted,
+			"Restarted query %s of view %s which was %s", query.ID, name, query.Status)
+	} else {
+		return observed, nil
+	}
+
+	if observed, err = adminClient.GetView(ctx, name); err != nil {
+		return nil, fmt.Errorf("failed to get view %s: %w", name, err)
+	}
+	if observed == nil {
+		return nil, fmt.Errorf("view %s not found after creating", name)
+	}
+	return observed, nil
+}
+
+// syncFinalizer makes sure the finalizer is only present with the Delete
+// deletion policy.
+func (r *ViewReconciler) syncFinalizer(ctx context.Context, view *hapi.View) error {
+	var updated bool
+	if view.Spec.DeletionPolicy == hapi.DeletionPolicyDelete {
+		updated = controllerutil.AddFinalizer(view, viewFinalizer)
+	} else {
+		updated = controllerutil.RemoveFinalizer(view, viewFinalizer)
+	}
+	if !updated {
+		return nil
+	}
+	return r.Update(ctx, view)
+}
+
+// finalize removes the view from HStreamDB according to the deletion policy.
+func (r *ViewReconciler) finalize(ctx context.Context, view *hapi.View) error {
+	if !controllerutil.ContainsFinalizer(view, viewFinalizer) {
+		return nil
+	}
+
+	if view.Spec.DeletionPolicy == hapi.DeletionPolicyDelete {
+		if err := r.deleteView(ctx, view); err != nil {
+			r.Recorder.Event(view, corev1.EventTypeWarning, hapi.ReasonAdminCommandFailed, err.Error())
+			return err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(view, viewFinalizer)
+	return r.Update(ctx, view)
+}
+
+func (r *ViewReconciler) deleteView(ctx context.Context, view *hapi.View) error {
+	hdb, err := getHStreamDB(ctx, r.Client, view.Namespace, view.Spec.HStreamDBRef)
+	if err != nil {
+		// The view is gone together with the HStreamDB.
+		return client.IgnoreNotFound(err)
+	}
+	if !hdb.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	name := view.GetViewName()
+	adminClient := admin.NewTypedAdminClient(r.AdminClientProvider.GetAdminClient(hdb))
+	observed, err := adminClient.GetView(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to get view %s: %w", name, err)
+	}
+	if observed == nil {
+		return nil
+	}
+	if err = adminClient.DeleteView(ctx, name); err != nil {
+		return fmt.Errorf("failed to delete view %s: %w", name, err)
+	}
+	r.Recorder.Eventf(view, corev1.EventTypeNormal, hapi.ReasonViewDeleted,
+		"Deleted view %s from HStreamDB %s", name, hdb.Name)
+	return nil
+}
+
+// updateStatus sets the Ready condition, whose type and observed generation
+// are filled in here.
+func (r *ViewReconciler) updateStatus(ctx context.Context, view *hapi.View, state hapi.SQLState, condition metav1.Condition) error {
+	view.Status.State = state
+	view.Status.ObservedGeneration = view.Generation
+	condition.Type = hapi.Ready
+	condition.ObservedGeneration = view.Generation
+	meta.SetStatusCondition(&view.Status.Conditions, condition)
+	return r.Status().Update(ctx, view)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ViewReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.AdminClientProvider = instrumentedAdminClientProvider{r.AdminClientProvider}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &hapi.View{}, hstreamDBRefField,
+		func(obj client.Object) []string {
+			return []string{hstreamDBRefKey(obj.GetNamespace(), obj.(*hapi.View).Spec.HStreamDBRef)}
+		}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&hapi.View{}).
+		// Create the views once their HStreamDB becomes ready.
+		Watches(&source.Kind{Type: &hapi.HStreamDB{}},
+			handler.EnqueueRequestsFromMapFunc(requestsForHStreamDB(mgr.GetClient(), &hapi.ViewList{})),
+			builder.WithPredicates(hstreamDBReadinessChanged)).
+		Complete(r)
+}
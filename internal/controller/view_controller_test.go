@@ -0,0 +1,107 @@
+package controller
+
+import (
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"github.com/hstreamdb/hstream-operator/mock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("controller/view", Ordered, func() {
+	const namespace = "view-test"
+	const sql = "SELECT user_id, COUNT(*) AS orders FROM orders GROUP BY user_id;"
+	var hdb *hapi.HStreamDB
+	var server *fakeServerAdminClient
+	var reconciler *ViewReconciler
+
+	newView := func(name string) *hapi.View {
+		return &hapi.View{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: hapi.ViewSpec{
+				HStreamDBRef: hapi.HStreamDBReference{Name: hdb.Name},
+				ViewName:     "orders_by_user",
+				SQL:          sql,
+			},
+		}
+	}
+
+	reconcileView := func(view *hapi.View) (ctrl.Result, error) {
+		res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(view)})
+		if getErr := k8sClient.Get(ctx, client.ObjectKeyFromObject(view), view); getErr != nil && !k8sErrors.IsNotFound(getErr) {
+			Expect(getErr).To(Succeed())
+		}
+		return res, err
+	}
+
+	BeforeAll(func() {
+		Expect(k8sClient.Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: namespace},
+		})).To(Succeed())
+
+		hdb = mock.CreateDefaultCR()
+		hdb.Name = "view-hdb"
+		hdb.Namespace = namespace
+		Expect(k8sClient.Create(ctx, hdb)).To(Succeed())
+		for _, condition := range []string{hapi.HServerReady, hapi.AdminServerReady} {
+			hdb.SetCondition(metav1.Condition{Type: condition, Status: metav1.ConditionTrue, Reason: "test"})
+		}
+		Expect(k8sClient.Status().Update(ctx, hdb)).To(Succeed())
+	})
+
+	BeforeEach(func() {
+		server = newFakeServerAdminClient()
+		reconciler = &ViewReconciler{
+			Client:              k8sClient,
+			Scheme:              k8sClient.Scheme(),
+			Recorder:            record.NewFakeRecorder(100),
+			AdminClientProvider: fakeAdminClientProvider{client: server},
+		}
+	})
+
+	It("should create the view and recreate it once the statement changes", func() {
+		view := newView("orders-by-user")
+		view.Spec.DeletionPolicy = hapi.DeletionPolicyDelete
+		Expect(k8sClient.Create(ctx, view)).To(Succeed())
+
+		res, err := reconcileView(view)
+		Expect(err).To(Succeed())
+		Expect(res.RequeueAfter).To(Equal(sqlResyncPeriod))
+		Expect(server.commands).To(ContainElement("sql CREATE VIEW orders_by_user AS " + sql))
+		Expect(server.views).To(HaveKey("orders_by_user"))
+		Expect(view.Status.State).To(Equal(hapi.SQLStateCreated))
+		Expect(view.Status.QueryID).To(Equal(server.views["orders_by_user"].QueryID))
+		Expect(view.Status.Conditions).To(ContainElement(And(
+			HaveField("Type", hapi.Ready),
+			HaveField("Status", metav1.ConditionTrue),
+			HaveField("Reason", hapi.ReasonQueryRunning),
+		)))
+
+		By("not creating the view again")
+		server.commands = nil
+		_, err = reconcileView(view)
+		Expect(err).To(Succeed())
+		Expect(server.commands).To(Equal([]string{"view list"}))
+
+		By("recreating the view once the statement changes")
+		view.Spec.SQL = "SELECT user_id, SUM(amount) AS total FROM orders GROUP BY user_id;"
+		Expect(k8sClient.Update(ctx, view)).To(Succeed())
+		_, err = reconcileView(view)
+		Expect(err).To(Succeed())
+		Expect(server.commands).To(ContainElements(
+			"view delete orders_by_user", "sql CREATE VIEW orders_by_user AS "+view.Spec.SQL))
+		Expect(view.Status.SQLHash).To(Equal(hashSQL(view.Spec.SQL)))
+
+		By("deleting the view")
+		Expect(k8sClient.Delete(ctx, view)).To(Succeed())
+		_, err = reconcileView(view)
+		Expect(err).To(Succeed())
+		Expect(server.views).To(BeEmpty())
+		Expect(k8sErrors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(view), view))).To(BeTrue())
+	})
+})
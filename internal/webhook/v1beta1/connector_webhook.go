@@ -0,0 +1,182 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/hstreamdb/hstream-operator/api/v1beta1"
+	"github.com/hstreamdb/hstream-operator/pkg/connectorgen"
+)
+
+//+kubebuilder:webhook:path=/validate-apps-hstream-io-v1beta1-connector,mutating=false,failurePolicy=fail,sideEffects=None,groups=apps.hstream.io,resources=connectors,verbs=create;update,versions=v1beta1,name=vconnector.hstream.io,admissionReviewVersions=v1
+
+// ConnectorValidator rejects the Connectors whose patches are clearly invalid.
+// The fields required by the connector type are only checked by the
+// controller, once the config of each stream is rendered.
+type ConnectorValidator struct {
+	Client client.Reader
+}
+
+// SetupConnectorWebhookWithManager registers the webhook validating Connectors.
+func SetupConnectorWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1beta1.Connector{}).
+		WithValidator(&ConnectorValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+func (v *ConnectorValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return v.validate(ctx, obj.(*v1beta1.Connector))
+}
+
+func (v *ConnectorValidator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) error {
+	return v.validate(ctx, newObj.(*v1beta1.Connector))
+}
+
+func (v *ConnectorValidator) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (v *ConnectorValidator) validate(ctx context.Context, connector *v1beta1.Connector) error {
+	var errs field.ErrorList
+
+	// The template may be created after the connector.
+	var template v1beta1.ConnectorTemplate
+	err := v.Client.Get(ctx, types.NamespacedName{Namespace: connector.Namespace, Name: connector.Spec.TemplateName}, &template)
+	switch {
+	case err == nil:
+		if template.Spec.Type != connector.Spec.Type {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "templateName"), connector.Spec.TemplateName,
+				fmt.Sprintf("the template is of type %s", template.Spec.Type)))
+		}
+	case !k8sErrors.IsNotFound(err):
+		return err
+	}
+
+	if (connector.Spec.HServerEndpoint == "") == (connector.Spec.HStreamDBRef == nil) {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "hstreamDBRef"), connector.Spec.HStreamDBRef,
+			"exactly one of hserverEndpoint and hstreamDBRef must be set"))
+	}
+
+	schema, typeErr, err := resolveConfigSchema(ctx, v.Client, connector.Spec.Type)
+	if err != nil {
+		return err
+	}
+	if typeErr != nil {
+		errs = append(errs, typeErr)
+	}
+
+	errs = append(errs, validatePatches(connector, schema)...)
+	if len(errs) == 0 {
+		return nil
+	}
+	return k8sErrors.NewInvalid(v1beta1.GroupVersion.WithKind("Connector").GroupKind(), connector.Name, errs)
+}
+
+// resolveConfigSchema returns the config schema of the connector type, or the
+// error of spec.type if the type is unknown or its schema is invalid.
+func resolveConfigSchema(ctx context.Context, c client.Reader, connectorType v1beta1.ConnectorType) (*spec.Schema, *field.Error, error) {
+	path := field.NewPath("spec", "type")
+
+	class, err := connectorgen.ResolveConnectorClass(ctx, c, connectorType)
+	if err != nil {
+		if errors.Is(err, connectorgen.ErrUnknownConnectorType) {
+			return nil, field.Invalid(path, connectorType, err.Error()), nil
+		}
+		return nil, nil, err
+	}
+	schema, err := connectorgen.ConfigSchema(class)
+	if err != nil {
+		return nil, field.Invalid(path, connectorType, err.Error()), nil
+	}
+	return schema, nil, nil
+}
+
+// validatePatches checks that the patches are of the patch type of the
+// connector and only refer to its streams.
+func validatePatches(connector *v1beta1.Connector, schema *spec.Schema) field.ErrorList {
+	var errs field.ErrorList
+	if connector.Spec.GlobalPatch != nil {
+		errs = append(errs, validatePatch(field.NewPath("spec", "globalPatch"),
+			connector.Spec.PatchType, schema, connector.Spec.GlobalPatch)...)
+	}
+
+	path := field.NewPath("spec", "patches")
+	if connector.Spec.Patches == nil {
+		return errs
+	}
+
+	var patches map[string]json.RawMessage
+	if err := json.Unmarshal(connector.Spec.Patches, &patches); err != nil {
+		return append(errs, field.Invalid(path, field.OmitValueType{},
+			"patches must be an object of the config patch of each stream"))
+	}
+
+	streams := make(map[string]bool, len(connector.Spec.Streams))
+	for _, stream := range connector.Spec.Streams {
+		streams[stream] = true
+	}
+
+	names := make([]string, 0, len(patches))
+	for stream := range patches {
+		names = append(names, stream)
+	}
+	sort.Strings(names)
+
+	for _, stream := range names {
+		if !streams[stream] {
+			errs = append(errs, field.NotSupported(path.Key(stream), stream, connector.Spec.Streams))
+			continue
+		}
+		errs = append(errs, validatePatch(path.Key(stream), connector.Spec.PatchType, schema, patches[stream])...)
+	}
+	return errs
+}
+
+// validatePatch checks that the patch is of the patch type. The values of a
+// merge patch must also match the schema of the connector type, while the
+// operations of a JSON Patch can only be checked once applied.
+func validatePatch(path *field.Path, patchType v1beta1.ConnectorPatchType, schema *spec.Schema, patch json.RawMessage) field.ErrorList {
+	if err := connectorgen.ValidatePatch(patchType, patch); err != nil {
+		return field.ErrorList{field.Invalid(path, field.OmitValueType{}, err.Error())}
+	}
+	if patchType == v1beta1.ConnectorPatchJSON {
+		return nil
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal(patch, &config); err != nil {
+		return field.ErrorList{field.Invalid(path, field.OmitValueType{}, err.Error())}
+	}
+	if err := connectorgen.ValidatePartialConfig(schema, config); err != nil {
+		return field.ErrorList{field.Invalid(path, field.OmitValueType{}, err.Error())}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package v1beta1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	hapi "github.com/hstreamdb/hstream-operator/api/v1alpha2"
+	"github.com/hstreamdb/hstream-operator/api/v1beta1"
+	"github.com/hstreamdb/hstream-operator/mock"
+)
+
+func newFakeClient(objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	Expect(v1beta1.AddToScheme(scheme)).To(Succeed())
+	return clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+var _ = Describe("webhook/connector", func() {
+	var validator *ConnectorValidator
+
+	BeforeEach(func() {
+		template := mock.CreateDefaultConnectorTemplate()
+		validator = &ConnectorValidator{Client: newFakeClient(&template)}
+	})
+
+	It("should accept a valid connector", func() {
+		connector := mock.CreateDefaultConnector("default")
+		connector.Spec.Patches = []byte(`{"stream01": {"index": "index01", "password": {"valueFrom": {"secretKeyRef": {"name": "es", "key": "password"}}}}}`)
+		Expect(validator.ValidateCreate(context.TODO(), &connector)).To(Succeed())
+
+		By("accepting a connector whose template is not created yet")
+		connector.Spec.TemplateName = "missing"
+		Expect(validator.ValidateUpdate(context.TODO(), &connector, &connector)).To(Succeed())
+	})
+
+	It("should require exactly one of the endpoint and the HStreamDB reference", func() {
+		connector := mock.CreateDefaultConnector("default")
+		connector.Spec.HStreamDBRef = &v1beta1.HStreamDBReference{HStreamDBReference: hapi.HStreamDBReference{Name: "hstreamdb"}}
+		Expect(validator.ValidateCreate(context.TODO(), &connector)).
+			To(MatchError(ContainSubstring("exactly one of hserverEndpoint and hstreamDBRef must be set")))
+
+		connector.Spec.HServerEndpoint = ""
+		Expect(validator.ValidateCreate(context.TODO(), &connector)).To(Succeed())
+
+		connector.Spec.HStreamDBRef = nil
+		Expect(validator.ValidateCreate(context.TODO(), &connector)).
+			To(MatchError(ContainSubstring("exactly one of hserverEndpoint and hstreamDBRef must be set")))
+	})
+
+	It("should reject the patches of unknown streams or invalid values", func() {
+		connector := mock.CreateDefaultConnector("default")
+		connector.Spec.Patches = []byte(`{"stream01": {"scheme": "ftp"}, "stream02": {"index": "index02"}}`)
+
+		err := validator.ValidateCreate(context.TODO(), &connector)
+		Expect(k8sErrors.IsInvalid(err)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring(`spec.patches[stream01]: Invalid value: invalid sink-elasticsearch config: scheme in body should be one of [http https]`)))
+		Expect(err).To(MatchError(ContainSubstring(`spec.patches[stream02]: Unsupported value: "stream02"`)))
+
+		By("rejecting patches which are not objects")
+		connector.Spec.Patches = []byte(`{"stream01": "index01"}`)
+		Expect(validator.ValidateCreate(context.TODO(), &connector)).To(MatchError(ContainSubstring("spec.patches")))
+	})
+
+	It("should validate the global patch and the patches of the patch type", func() {
+		connector := mock.CreateDefaultConnector("default")
+		connector.Spec.GlobalPatch = []byte(`{"scheme": "ftp", "index": null}`)
+
+		Expect(validator.ValidateCreate(context.TODO(), &connector)).
+			To(MatchError(ContainSubstring(`spec.globalPatch: Invalid value: invalid sink-elasticsearch config: scheme in body should be one of [http https]`)))
+
+		By("accepting JSON Patches")
+		connector.Spec.PatchType = v1beta1.ConnectorPatchJSON
+		connector.Spec.GlobalPatch = []byte(`[{"op": "replace", "path": "/scheme", "value": "https"}]`)
+		connector.Spec.Patches = []byte(`{"stream01": [{"op": "remove", "path": "/index"}]}`)
+		Expect(validator.ValidateCreate(context.TODO(), &connector)).To(Succeed())
+
+		connector.Spec.Patches = []byte(`{"stream01": {"index": "index01"}}`)
+		Expect(validator.ValidateCreate(context.TODO(), &connector)).
+			To(MatchError(ContainSubstring(`spec.patches[stream01]: Invalid value: a JSON Patch must be a list of operations`)))
+	})
+
+	It("should reject a template of another type", func() {
+		connector := mock.CreateDefaultConnector("default")
+		connector.Spec.Type = v1beta1.SinkMySQL
+
+		Expect(validator.ValidateCreate(context.TODO(), &connector)).
+			To(MatchError(ContainSubstring("the template is of type sink-elasticsearch")))
+	})
+
+	It("should reject the types without a ConnectorClass", func() {
+		connector := mock.CreateDefaultConnector("default")
+		connector.Spec.Type = "sink-unknown"
+		connector.Spec.TemplateName = "missing"
+
+		Expect(validator.ValidateCreate(context.TODO(), &connector)).
+			To(MatchError(ContainSubstring(`spec.type: Invalid value: "sink-unknown": unknown connector type`)))
+
+		By("accepting the types registered by a ConnectorClass")
+		validator.Client = newFakeClient(&v1beta1.ConnectorClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "sink-unknown"},
+			Spec:       v1beta1.ConnectorClassSpec{Image: "example/sink-unknown:v1"},
+		})
+		Expect(validator.ValidateCreate(context.TODO(), &connector)).To(Succeed())
+	})
+})
+
+var _ = Describe("webhook/connectortemplate", func() {
+	var validator *ConnectorTemplateValidator
+
+	BeforeEach(func() {
+		validator = &ConnectorTemplateValidator{Client: newFakeClient()}
+	})
+
+	It("should validate the config against the schema of the type", func() {
+		template := mock.CreateDefaultConnectorTemplate()
+		Expect(validator.ValidateCreate(context.TODO(), &template)).To(Succeed())
+
+		template.Spec.Config = `{"hosts": 9200}`
+		Expect(validator.ValidateUpdate(context.TODO(), &template, &template)).
+			To(MatchError(ContainSubstring("hosts in body must be of type string")))
+
+		template.Spec.Config = `["localhost:9200"]`
+		Expect(validator.ValidateCreate(context.TODO(), &template)).
+			To(MatchError(ContainSubstring("config must be a JSON object")))
+	})
+})
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"encoding/json"
+
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/hstreamdb/hstream-operator/api/v1beta1"
+	"github.com/hstreamdb/hstream-operator/pkg/connectorgen"
+)
+
+//+kubebuilder:webhook:path=/validate-apps-hstream-io-v1beta1-connectortemplate,mutating=false,failurePolicy=fail,sideEffects=None,groups=apps.hstream.io,resources=connectortemplates,verbs=create;update,versions=v1beta1,name=vconnectortemplate.hstream.io,admissionReviewVersions=v1
+
+// ConnectorTemplateValidator rejects the ConnectorTemplates whose config is not
+// a JSON object or does not match the schema of the connector type.
+type ConnectorTemplateValidator struct {
+	Client client.Reader
+}
+
+// SetupConnectorTemplateWebhookWithManager registers the webhook validating
+// ConnectorTemplates.
+func SetupConnectorTemplateWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1beta1.ConnectorTemplate{}).
+		WithValidator(&ConnectorTemplateValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+func (v *ConnectorTemplateValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return v.validate(ctx, obj.(*v1beta1.ConnectorTemplate))
+}
+
+func (v *ConnectorTemplateValidator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) error {
+	return v.validate(ctx, newObj.(*v1beta1.ConnectorTemplate))
+}
+
+func (v *ConnectorTemplateValidator) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (v *ConnectorTemplateValidator) validate(ctx context.Context, template *v1beta1.ConnectorTemplate) error {
+	path := field.NewPath("spec", "config")
+
+	var errs field.ErrorList
+	schema, typeErr, err := resolveConfigSchema(ctx, v.Client, template.Spec.Type)
+	if err != nil {
+		return err
+	}
+	if typeErr != nil {
+		errs = append(errs, typeErr)
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(template.Spec.Config), &config); err != nil {
+		errs = append(errs, field.Invalid(path, field.OmitValueType{}, "config must be a JSON object: "+err.Error()))
+	} else if err = connectorgen.ValidatePartialConfig(schema, config); err != nil {
+		errs = append(errs, field.Invalid(path, field.OmitValueType{}, err.Error()))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return k8sErrors.NewInvalid(v1beta1.GroupVersion.WithKind("ConnectorTemplate").GroupKind(), template.Name, errs)
+}
@@ -28,11 +28,12 @@ func CreateDefaultConnector(ns string) v1beta1.Connector {
 			Namespace: ns,
 		},
 		Spec: v1beta1.ConnectorSpec{
-			Type:         "sink-elasticsearch", // We only support this type for now.
+			Type:         "sink-elasticsearch",
 			TemplateName: "test-connector-template",
 			Streams: []string{
 				"stream01",
 			},
+			HServerEndpoint: "hstreamdb-hserver:6570",
 		},
 	}
 }
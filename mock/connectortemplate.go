@@ -28,7 +28,7 @@ func CreateDefaultConnectorTemplate() v1beta1.ConnectorTemplate {
 			Namespace: "default",
 		},
 		Spec: v1beta1.ConnectorTemplateSpec{
-			Type: "sink-elasticsearch", // We only support this type for now.
+			Type: "sink-elasticsearch",
 			Config: `
 			{
 				"auth": "basic",
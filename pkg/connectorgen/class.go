@@ -0,0 +1,98 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectorgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/hstreamdb/hstream-operator/api/v1beta1"
+)
+
+// ErrUnknownConnectorType means there is neither a ConnectorClass nor a
+// built-in connector type of the name.
+var ErrUnknownConnectorType = errors.New("unknown connector type")
+
+// BuiltinConnectorClass returns the class of a built-in connector type.
+func BuiltinConnectorClass(connectorType v1beta1.ConnectorType) (*v1beta1.ConnectorClass, bool) {
+	image, ok := v1beta1.ConnectorImageMap[connectorType]
+	if !ok {
+		return nil, false
+	}
+
+	return &v1beta1.ConnectorClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: string(connectorType),
+		},
+		Spec: v1beta1.ConnectorClassSpec{
+			Image: image,
+			Ports: []corev1.ContainerPort{
+				{
+					ContainerPort: v1beta1.ConnectorContainerPortMap[connectorType],
+				},
+			},
+			Schema: builtinSchema(connectorType),
+		},
+	}, true
+}
+
+// ResolveConnectorClass returns the ConnectorClass named after the connector
+// type, or the class of the built-in connector type if there is no such
+// ConnectorClass.
+func ResolveConnectorClass(ctx context.Context, c client.Reader, connectorType v1beta1.ConnectorType) (*v1beta1.ConnectorClass, error) {
+	class := &v1beta1.ConnectorClass{}
+	err := c.Get(ctx, types.NamespacedName{Name: string(connectorType)}, class)
+	if err == nil {
+		return class, nil
+	}
+	if !k8sErrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	if builtin, ok := BuiltinConnectorClass(connectorType); ok {
+		return builtin, nil
+	}
+	return nil, fmt.Errorf("%w %q, create a ConnectorClass of the name first", ErrUnknownConnectorType, connectorType)
+}
+
+// ClassContainer generates the container of the connector from its class,
+// with the config mounted from the volume named configName.
+func ClassContainer(connector *v1beta1.Connector, class *v1beta1.ConnectorClass, name, configName string) corev1.Container {
+	return corev1.Container{
+		Name:  name,
+		Image: addImageRegistry(class.Spec.Image, connector.Spec.ImageRegistry),
+		Args:  append([]string(nil), class.Spec.GetArgs()...),
+		Ports: append([]corev1.ContainerPort(nil), class.Spec.Ports...),
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      configName,
+				MountPath: class.Spec.GetConfigMountPath(),
+			},
+			{
+				Name:      "data",
+				MountPath: class.Spec.GetDataMountPath(),
+			},
+		},
+	}
+}
@@ -0,0 +1,108 @@
+package connectorgen
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/hstreamdb/hstream-operator/api/v1beta1"
+)
+
+var _ = Describe("connectorgen/class", func() {
+	newClass := func(name string) *v1beta1.ConnectorClass {
+		return &v1beta1.ConnectorClass{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1beta1.ConnectorClassSpec{
+				Image: "example/" + name + ":v1",
+				Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+			},
+		}
+	}
+
+	It("should resolve the connector class of a type", func() {
+		scheme := runtime.NewScheme()
+		Expect(v1beta1.AddToScheme(scheme)).To(Succeed())
+		c := clientfake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(newClass("sink-inhouse"), newClass(string(v1beta1.SinkMySQL))).Build()
+
+		class, err := ResolveConnectorClass(context.TODO(), c, "sink-inhouse")
+		Expect(err).To(Succeed())
+		Expect(class.Spec.Image).To(Equal("example/sink-inhouse:v1"))
+
+		By("preferring the ConnectorClass to the built-in type")
+		class, err = ResolveConnectorClass(context.TODO(), c, v1beta1.SinkMySQL)
+		Expect(err).To(Succeed())
+		Expect(class.Spec.Image).To(Equal("example/sink-mysql:v1"))
+
+		By("falling back to the built-in type")
+		class, err = ResolveConnectorClass(context.TODO(), c, v1beta1.SinkElaticsearch)
+		Expect(err).To(Succeed())
+		Expect(class.Spec.Image).To(Equal("hstreamdb/sink-elasticsearch:standalone"))
+		Expect(class.Spec.Ports[0].ContainerPort).To(Equal(int32(9200)))
+		Expect(class.Spec.Schema).NotTo(BeEmpty())
+
+		_, err = ResolveConnectorClass(context.TODO(), c, "sink-unknown")
+		Expect(err).To(MatchError(ErrUnknownConnectorType))
+	})
+
+	It("should generate the container of the connector from its class", func() {
+		connector := &v1beta1.Connector{
+			Spec: v1beta1.ConnectorSpec{
+				Type:          "sink-inhouse",
+				ImageRegistry: pointer.String("registry.example.com"),
+			},
+		}
+		class := newClass("sink-inhouse")
+
+		container := ClassContainer(connector, class, "test", "config")
+		Expect(container.Image).To(Equal("registry.example.com/example/sink-inhouse:v1"))
+		Expect(container.Args).To(Equal([]string{"run", "--config /data/config/config.json"}))
+		Expect(container.Ports).To(Equal(class.Spec.Ports))
+		Expect(container.VolumeMounts[0]).To(Equal(corev1.VolumeMount{Name: "config", MountPath: "/data/config"}))
+
+		Expect(container.VolumeMounts[1]).To(Equal(corev1.VolumeMount{Name: "data", MountPath: "/data"}))
+
+		By("mounting the config and the data at the paths of the class")
+		class.Spec.ConfigMountPath = "/etc/connector"
+		class.Spec.DataMountPath = "/var/lib/connector"
+		container = ClassContainer(connector, class, "test", "config")
+		Expect(container.Args).To(Equal([]string{"run", "--config /etc/connector/config.json"}))
+		Expect(container.VolumeMounts[0].MountPath).To(Equal("/etc/connector"))
+		Expect(container.VolumeMounts[1].MountPath).To(Equal("/var/lib/connector"))
+
+		class.Spec.Args = []string{"start", "-c", "/etc/connector/config.json"}
+		Expect(ClassContainer(connector, class, "test", "config").Args).To(Equal(class.Spec.Args))
+	})
+
+	DescribeTable("should generate the container of the built-in connector type",
+		func(connectorType v1beta1.ConnectorType, image string) {
+			connector := &v1beta1.Connector{
+				Spec: v1beta1.ConnectorSpec{
+					Type: connectorType,
+				},
+			}
+			class, ok := BuiltinConnectorClass(connectorType)
+			Expect(ok).To(BeTrue())
+			container := ClassContainer(connector, class, "test", "test")
+
+			Expect(container.Name).To(Equal("test"))
+			Expect(container.Image).To(Equal(image))
+			Expect(container.Args).To(ContainElement("--config /data/config/config.json"))
+		},
+		Entry("sink-elasticsearch", v1beta1.SinkElaticsearch, "hstreamdb/sink-elasticsearch:standalone"),
+		Entry("sink-mysql", v1beta1.SinkMySQL, "hstreamdb/sink-mysql:standalone"),
+		Entry("sink-postgresql", v1beta1.SinkPostgreSQL, "hstreamdb/sink-postgresql:standalone"),
+		Entry("sink-mongodb", v1beta1.SinkMongoDB, "hstreamdb/sink-mongodb:standalone"),
+		Entry("sink-kafka", v1beta1.SinkKafka, "hstreamdb/sink-kafka:standalone"),
+		Entry("sink-s3", v1beta1.SinkS3, "hstreamdb/sink-s3:standalone"),
+		Entry("source-mysql", v1beta1.SourceMySQL, "hstreamdb/source-mysql:standalone"),
+		Entry("source-postgresql", v1beta1.SourcePostgreSQL, "hstreamdb/source-postgresql:standalone"),
+		Entry("source-mongodb", v1beta1.SourceMongoDB, "hstreamdb/source-mongodb:standalone"),
+	)
+})
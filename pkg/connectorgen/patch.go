@@ -0,0 +1,78 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectorgen
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+
+	"github.com/hstreamdb/hstream-operator/api/v1beta1"
+)
+
+// ApplyPatch applies the patch to the config according to the patch type,
+// which defaults to a JSON merge patch. The config is not modified.
+func ApplyPatch(config map[string]interface{}, patchType v1beta1.ConnectorPatchType, patch json.RawMessage) (map[string]interface{}, error) {
+	if len(patch) == 0 {
+		return config, nil
+	}
+	if err := ValidatePatch(patchType, patch); err != nil {
+		return nil, err
+	}
+
+	doc, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	switch patchType {
+	case v1beta1.ConnectorPatchJSON:
+		operations, _ := jsonpatch.DecodePatch(patch)
+		patched, err = operations.Apply(doc)
+	default:
+		patched, err = jsonpatch.MergePatch(doc, patch)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fail to apply patch: %w", err)
+	}
+
+	var result map[string]interface{}
+	if err = json.Unmarshal(patched, &result); err != nil || result == nil {
+		return nil, errors.New("the patched config is not a JSON object")
+	}
+	return result, nil
+}
+
+// ValidatePatch checks that the patch is a JSON object for a merge patch, or a
+// list of operations for a JSON Patch.
+func ValidatePatch(patchType v1beta1.ConnectorPatchType, patch json.RawMessage) error {
+	switch patchType {
+	case v1beta1.ConnectorPatchJSON:
+		if _, err := jsonpatch.DecodePatch(patch); err != nil {
+			return fmt.Errorf("a JSON Patch must be a list of operations: %w", err)
+		}
+	default:
+		var object map[string]interface{}
+		if err := json.Unmarshal(patch, &object); err != nil || object == nil {
+			return errors.New("a merge patch must be a JSON object")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package connectorgen
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hstreamdb/hstream-operator/api/v1beta1"
+)
+
+var _ = Describe("connectorgen/patch", func() {
+	var config map[string]interface{}
+
+	BeforeEach(func() {
+		Expect(json.Unmarshal([]byte(`{
+			"stream": "stream01",
+			"auth": {"username": "elastic", "password": "changeme"},
+			"hosts": ["es-0:9200", "es-1:9200"],
+			"buffer": {"batch": {"maxAge": 0, "maxBytesSize": 0}}
+		}`), &config)).To(Succeed())
+	})
+
+	apply := func(patchType v1beta1.ConnectorPatchType, patch string) map[string]interface{} {
+		patched, err := ApplyPatch(config, patchType, json.RawMessage(patch))
+		Expect(err).To(Succeed())
+		return patched
+	}
+
+	It("should merge the nested objects of a merge patch", func() {
+		patched := apply(v1beta1.ConnectorPatchMerge, `{"auth": {"password": "secret"}, "buffer": {"batch": {"maxAge": 100}}}`)
+
+		Expect(patched["auth"]).To(Equal(map[string]interface{}{"username": "elastic", "password": "secret"}))
+		Expect(patched["buffer"]).To(Equal(map[string]interface{}{
+			"batch": map[string]interface{}{"maxAge": float64(100), "maxBytesSize": float64(0)},
+		}))
+		Expect(patched["hosts"]).To(Equal(config["hosts"]))
+
+		By("not modifying the config")
+		Expect(config["auth"]).To(HaveKeyWithValue("password", "changeme"))
+	})
+
+	It("should replace the arrays and remove the null fields of a merge patch", func() {
+		patched := apply("", `{"hosts": ["es-2:9200"], "auth": {"password": null}, "buffer": null}`)
+
+		Expect(patched["hosts"]).To(Equal([]interface{}{"es-2:9200"}))
+		Expect(patched["auth"]).To(Equal(map[string]interface{}{"username": "elastic"}))
+		Expect(patched).NotTo(HaveKey("buffer"))
+	})
+
+	It("should apply the operations of a JSON Patch", func() {
+		patched := apply(v1beta1.ConnectorPatchJSON, `[
+			{"op": "replace", "path": "/auth/password", "value": "secret"},
+			{"op": "add", "path": "/hosts/-", "value": "es-2:9200"},
+			{"op": "remove", "path": "/hosts/0"},
+			{"op": "remove", "path": "/buffer/batch/maxAge"}
+		]`)
+
+		Expect(patched["auth"]).To(Equal(map[string]interface{}{"username": "elastic", "password": "secret"}))
+		Expect(patched["hosts"]).To(Equal([]interface{}{"es-1:9200", "es-2:9200"}))
+		Expect(patched["buffer"]).To(Equal(map[string]interface{}{
+			"batch": map[string]interface{}{"maxBytesSize": float64(0)},
+		}))
+	})
+
+	It("should reject the patches not of the patch type", func() {
+		_, err := ApplyPatch(config, v1beta1.ConnectorPatchMerge, json.RawMessage(`[{"op": "remove", "path": "/hosts"}]`))
+		Expect(err).To(MatchError("a merge patch must be a JSON object"))
+
+		_, err = ApplyPatch(config, v1beta1.ConnectorPatchJSON, json.RawMessage(`{"hosts": []}`))
+		Expect(err).To(MatchError(ContainSubstring("a JSON Patch must be a list of operations")))
+
+		_, err = ApplyPatch(config, v1beta1.ConnectorPatchJSON, json.RawMessage(`[{"op": "replace", "path": "/index", "value": "index01"}]`))
+		Expect(err).To(MatchError(ContainSubstring("fail to apply patch")))
+
+		_, err = ApplyPatch(config, v1beta1.ConnectorPatchJSON, json.RawMessage(`[{"op": "replace", "path": "", "value": []}]`))
+		Expect(err).To(MatchError("the patched config is not a JSON object"))
+	})
+
+	It("should leave the config as it is without a patch", func() {
+		Expect(apply(v1beta1.ConnectorPatchJSON, ``)).To(Equal(config))
+	})
+})
@@ -0,0 +1,138 @@
+/*
+Copyright 2023 HStream Operator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectorgen
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	validationerrors "k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+
+	"github.com/hstreamdb/hstream-operator/api/v1beta1"
+)
+
+// ErrInvalidConfigSchema means the schema declared by a ConnectorClass is not a
+// valid JSON Schema.
+var ErrInvalidConfigSchema = errors.New("invalid config schema")
+
+// schemaFS holds the JSON Schema of the config of each built-in connector
+// type, named after the type.
+//
+//go:embed schemas/*.json
+var schemaFS embed.FS
+
+// builtinSchema returns the JSON Schema of a built-in connector type.
+func builtinSchema(connectorType v1beta1.ConnectorType) json.RawMessage {
+	data, err := schemaFS.ReadFile("schemas/" + string(connectorType) + ".json")
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// ConfigSchema returns the JSON Schema of the config of the connector class,
+// or nil if the class does not declare one.
+func ConfigSchema(class *v1beta1.ConnectorClass) (*spec.Schema, error) {
+	if len(class.Spec.Schema) == 0 {
+		return nil, nil
+	}
+
+	schema := &spec.Schema{}
+	if err := json.Unmarshal(class.Spec.Schema, schema); err != nil {
+		return nil, fmt.Errorf("%w of connector type %q: %s", ErrInvalidConfigSchema, class.Name, err.Error())
+	}
+	if schema.Title == "" {
+		schema.Title = class.Name
+	}
+	return schema, nil
+}
+
+// ValidateConfig validates the config rendered for a stream, whose secret
+// references are resolved, against the schema. A nil schema accepts any config.
+func ValidateConfig(schema *spec.Schema, config map[string]interface{}) error {
+	return validateConfig(schema, config, false)
+}
+
+// ValidatePartialConfig validates a part of the config, such as a template or
+// the merge patch of a stream, against the schema. The required fields and the
+// values taken from Secrets are not checked, since they may only be known once
+// the config is rendered, nor are the nulls removing fields in a merge patch.
+func ValidatePartialConfig(schema *spec.Schema, config map[string]interface{}) error {
+	return validateConfig(schema, withoutPlaceholders(config).(map[string]interface{}), true)
+}
+
+func validateConfig(schema *spec.Schema, config map[string]interface{}, partial bool) error {
+	if schema == nil {
+		return nil
+	}
+
+	result := validate.NewSchemaValidator(schema, nil, "", strfmt.Default).Validate(config)
+	var messages []string
+	for _, err := range result.Errors {
+		if e, ok := err.(*validationerrors.Validation); ok && partial && e.Code() == validationerrors.RequiredFailCode {
+			continue
+		}
+		messages = append(messages, strings.TrimPrefix(err.Error(), "."))
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	sort.Strings(messages)
+	return fmt.Errorf("invalid %s config: %s", schema.Title, strings.Join(messages, "; "))
+}
+
+// withoutPlaceholders returns a copy of the config value without the values of
+// the form {"valueFrom": {...}} and the nulls, both in objects and in arrays.
+func withoutPlaceholders(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		stripped := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			if !isPlaceholder(v) {
+				stripped[k] = withoutPlaceholders(v)
+			}
+		}
+		return stripped
+	case []interface{}:
+		stripped := make([]interface{}, 0, len(value))
+		for _, v := range value {
+			if !isPlaceholder(v) {
+				stripped = append(stripped, withoutPlaceholders(v))
+			}
+		}
+		return stripped
+	default:
+		return value
+	}
+}
+
+// isPlaceholder reports whether the config value is null or of the form
+// {"valueFrom": {...}}, which is only known once the config is rendered.
+func isPlaceholder(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	object, ok := value.(map[string]interface{})
+	return ok && len(object) == 1 && object["valueFrom"] != nil
+}
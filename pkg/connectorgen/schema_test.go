@@ -0,0 +1,104 @@
+package connectorgen
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+
+	"github.com/hstreamdb/hstream-operator/api/v1beta1"
+)
+
+var _ = Describe("connectorgen/schema", func() {
+	parse := func(config string) map[string]interface{} {
+		var parsed map[string]interface{}
+		Expect(json.Unmarshal([]byte(config), &parsed)).To(Succeed())
+		return parsed
+	}
+
+	schemaOf := func(connectorType v1beta1.ConnectorType) *spec.Schema {
+		class, ok := BuiltinConnectorClass(connectorType)
+		Expect(ok).To(BeTrue())
+		schema, err := ConfigSchema(class)
+		Expect(err).To(Succeed())
+		return schema
+	}
+
+	It("should ship a config schema for each connector type", func() {
+		for connectorType := range v1beta1.ConnectorImageMap {
+			schema := schemaOf(connectorType)
+			Expect(schema).NotTo(BeNil())
+			Expect(schema.Required).To(ContainElement("stream"))
+		}
+	})
+
+	It("should use the schema declared by a connector class", func() {
+		class := &v1beta1.ConnectorClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "sink-inhouse"},
+			Spec: v1beta1.ConnectorClassSpec{
+				Image:  "example/sink-inhouse:v1",
+				Schema: []byte(`{"type": "object", "required": ["endpoint"]}`),
+			},
+		}
+		schema, err := ConfigSchema(class)
+		Expect(err).To(Succeed())
+		Expect(ValidateConfig(schema, parse(`{"stream": "stream01"}`))).
+			To(MatchError(ContainSubstring("invalid sink-inhouse config: endpoint in body is required")))
+
+		By("accepting any config if the class declares no schema")
+		class.Spec.Schema = nil
+		schema, err = ConfigSchema(class)
+		Expect(err).To(Succeed())
+		Expect(schema).To(BeNil())
+		Expect(ValidateConfig(schema, parse(`{"stream": "stream01"}`))).To(Succeed())
+
+		By("rejecting a schema which is not a JSON Schema")
+		class.Spec.Schema = []byte(`{"type": 1}`)
+		_, err = ConfigSchema(class)
+		Expect(err).To(MatchError(ErrInvalidConfigSchema))
+	})
+
+	It("should validate the rendered config", func() {
+		Expect(ValidateConfig(schemaOf(v1beta1.SinkElaticsearch), parse(`{
+			"stream": "stream01",
+			"hosts": "localhost:9200",
+			"scheme": "http",
+			"task.error.maxRetries": 3
+		}`))).To(Succeed())
+
+		err := ValidateConfig(schemaOf(v1beta1.SinkElaticsearch), parse(`{
+			"stream": "stream01",
+			"scheme": "ftp",
+			"task.error.maxRetries": "3"
+		}`))
+		Expect(err).To(MatchError(ContainSubstring("hosts in body is required")))
+		Expect(err).To(MatchError(ContainSubstring("scheme in body should be one of [http https]")))
+		Expect(err).To(MatchError(ContainSubstring("task.error.maxRetries in body must be of type integer")))
+	})
+
+	It("should skip the required fields and secret references of a partial config", func() {
+		Expect(ValidatePartialConfig(schemaOf(v1beta1.SinkMySQL), parse(`{
+			"port": 3306,
+			"password": {"valueFrom": {"secretKeyRef": {"name": "mysql", "key": "password"}}}
+		}`))).To(Succeed())
+
+		Expect(ValidatePartialConfig(schemaOf(v1beta1.SinkMySQL), parse(`{"port": 65536}`))).
+			To(MatchError(ContainSubstring("port in body should be less than or equal to 65535")))
+
+		By("skipping the nulls and secret references in arrays")
+		schema, err := ConfigSchema(&v1beta1.ConnectorClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "sink-inhouse"},
+			Spec: v1beta1.ConnectorClassSpec{
+				Schema: []byte(`{"type": "object", "properties": {"hosts": {"type": "array", "items": {"type": "string"}}}}`),
+			},
+		})
+		Expect(err).To(Succeed())
+		Expect(ValidatePartialConfig(schema, parse(`{
+			"hosts": ["localhost:9200", null, {"valueFrom": {"secretKeyRef": {"name": "es", "key": "host"}}}]
+		}`))).To(Succeed())
+		Expect(ValidatePartialConfig(schema, parse(`{"hosts": ["localhost:9200", 9200]}`))).
+			To(MatchError(ContainSubstring("hosts[1] in body must be of type string")))
+	})
+})
@@ -18,6 +18,7 @@ package executor
 
 import (
 	"strings"
+	"time"
 )
 
 type Command struct {
@@ -25,8 +26,9 @@ type Command struct {
 
 	Args []string
 
-	// Timeout specifies the Timeout for running commands.
-	// Timeout time.Duration
+	// Timeout specifies the Timeout for running commands, it overrides the
+	// timeout of the executor if set.
+	Timeout time.Duration
 }
 
 func (c *Command) GetCommand() []string {
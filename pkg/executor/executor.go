@@ -19,34 +19,132 @@ package executor
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
+	utilexec "k8s.io/client-go/util/exec"
 )
 
+const (
+	// DefaultTimeout bounds every attempt of a call.
+	DefaultTimeout = time.Minute
+)
+
+// DefaultBackoff retries the transient errors of the API server 3 times.
+var DefaultBackoff = wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    4,
+	Cap:      5 * time.Second,
+}
+
+// Executor runs commands in pods and accesses services through the API server.
+type Executor interface {
+	// RunCommandInPodWithContext runs the command in the first container of the
+	// pod. An *ExitError is returned if the command exits with a non-zero code.
+	RunCommandInPodWithContext(ctx context.Context, podName, namespace string, command Command) (*Result, error)
+
+	// AccessServiceProxyWithContext gets the path of the service through the
+	// proxy of the API server.
+	AccessServiceProxyWithContext(ctx context.Context, namespace, serviceName, path string) ([]byte, error)
+}
+
+// Result is the output of a command run in a pod.
+type Result struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// ExitError is returned when a command exits with a non-zero code.
+type ExitError struct {
+	Command string
+	Result  *Result
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("command %q exited with code %d, stderr: %s", e.Command, e.Result.ExitCode, e.Result.Stderr)
+}
+
+// Option configures a RemoteExecutor.
+type Option func(*RemoteExecutor)
+
+// WithTimeout sets the timeout of every attempt of a call.
+func WithTimeout(timeout time.Duration) Option {
+	return func(e *RemoteExecutor) {
+		e.timeout = timeout
+	}
+}
+
+// WithBackoff sets the retry policy of the transient errors, Steps is the
+// maximum number of attempts.
+func WithBackoff(backoff wait.Backoff) Option {
+	return func(e *RemoteExecutor) {
+		e.backoff = backoff
+	}
+}
+
 type RemoteExecutor struct {
 	Config    *rest.Config
 	Clientset *kubernetes.Clientset
+
+	timeout time.Duration
+	backoff wait.Backoff
+
+	// newStreamExecutor creates the executor of the exec request, which stops
+	// streaming once the context is done. It is replaced in tests.
+	newStreamExecutor func(ctx context.Context, url *url.URL) (remotecommand.Executor, error)
 }
 
-func NewRemoteExecutor(config *rest.Config) (*RemoteExecutor, error) {
+var _ Executor = &RemoteExecutor{}
+
+func NewRemoteExecutor(config *rest.Config, opts ...Option) (*RemoteExecutor, error) {
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, err
 	}
 
-	return &RemoteExecutor{
+	e := &RemoteExecutor{
 		Config:    config,
 		Clientset: clientset,
-	}, nil
+		timeout:   DefaultTimeout,
+		backoff:   DefaultBackoff,
+		newStreamExecutor: func(ctx context.Context, url *url.URL) (remotecommand.Executor, error) {
+			return newSPDYExecutorWithContext(ctx, config, url)
+		},
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e, nil
 }
 
+// RunCommandInPod runs the command and returns its stdout.
+//
+// Deprecated: use RunCommandInPodWithContext.
 func (e *RemoteExecutor) RunCommandInPod(podName, namespace string, command Command) (string, error) {
+	result, err := e.RunCommandInPodWithContext(context.TODO(), podName, namespace, command)
+	if err != nil {
+		return "", err
+	}
+	return result.Stdout, nil
+}
+
+func (e *RemoteExecutor) RunCommandInPodWithContext(ctx context.Context, podName, namespace string, command Command) (*Result, error) {
 	req := e.Clientset.CoreV1().RESTClient().Post().
 		Namespace(namespace).
 		Resource("pods").
@@ -60,29 +158,156 @@ func (e *RemoteExecutor) RunCommandInPod(podName, namespace string, command Comm
 
 	req.VersionedParams(option, scheme.ParameterCodec)
 
-	exec, err := remotecommand.NewSPDYExecutor(e.Config, http.MethodPost, req.URL())
-	if err != nil {
-		return "", fmt.Errorf("an error occurred while creating the executor: %w", err)
+	return e.exec(ctx, req.URL(), command)
+}
+
+// exec streams the command with retries. Only the transient errors of the API
+// server are retried, they are returned before the command starts.
+func (e *RemoteExecutor) exec(ctx context.Context, url *url.URL, command Command) (result *Result, err error) {
+	timeout := e.timeout
+	if command.Timeout > 0 {
+		timeout = command.Timeout
 	}
 
-	var stdout, stderr bytes.Buffer
+	err = e.retry(ctx, timeout, func(ctx context.Context) error {
+		exec, err := e.newStreamExecutor(ctx, url)
+		if err != nil {
+			return fmt.Errorf("an error occurred while creating the executor: %w", err)
+		}
 
-	err = exec.Stream(remotecommand.StreamOptions{
-		Stdout: &stdout,
-		Stderr: &stderr,
+		result, err = stream(ctx, exec)
+		return err
 	})
-	if err != nil || stderr.Len() != 0 {
-		return "", fmt.Errorf("an error occurred while executing the command: %w, command: %s, stderr: %s", err, command.ToString(), stderr.String())
+	if err == nil {
+		return result, nil
 	}
 
-	return stdout.String(), nil
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) && exitErr.Exited() {
+		result.ExitCode = exitErr.ExitStatus()
+		return result, &ExitError{Command: command.ToString(), Result: result}
+	}
+	return result, fmt.Errorf("an error occurred while executing the command: %w, command: %s", err, command.ToString())
+}
+
+// stream runs the executor until it finishes or the context is done. The
+// executor closes its connection once the context is done, so that it is
+// waited for instead of being leaked along with its connection.
+func stream(ctx context.Context, exec remotecommand.Executor) (*Result, error) {
+	var stdout, stderr bytes.Buffer
+	done := make(chan error, 1)
+	go func() {
+		done <- exec.Stream(remotecommand.StreamOptions{
+			Stdout: &stdout,
+			Stderr: &stderr,
+		})
+	}()
+
+	select {
+	case err := <-done:
+		return &Result{Stdout: stdout.String(), Stderr: stderr.String()}, err
+	case <-ctx.Done():
+		<-done
+		return &Result{}, ctx.Err()
+	}
+}
+
+// newSPDYExecutorWithContext creates a SPDY executor bound to the context. The
+// executor of this client-go version can not be canceled, so the context is
+// set on the upgrade request, which aborts the dial, and the connection is
+// closed once the context is done, which ends the streaming.
+func newSPDYExecutorWithContext(ctx context.Context, config *rest.Config, url *url.URL) (remotecommand.Executor, error) {
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return nil, err
+	}
+	return remotecommand.NewSPDYExecutorForTransports(
+		contextRoundTripper{ctx: ctx, RoundTripper: transport},
+		contextUpgrader{ctx: ctx, Upgrader: upgrader},
+		http.MethodPost, url)
+}
+
+// contextRoundTripper sends the requests with its context.
+type contextRoundTripper struct {
+	http.RoundTripper
+	ctx context.Context
+}
+
+func (rt contextRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return rt.RoundTripper.RoundTrip(req.WithContext(rt.ctx))
+}
+
+// contextUpgrader closes the connections it creates once its context is done.
+type contextUpgrader struct {
+	spdy.Upgrader
+	ctx context.Context
 }
 
+func (u contextUpgrader) NewConnection(resp *http.Response) (httpstream.Connection, error) {
+	conn, err := u.Upgrader.NewConnection(resp)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		select {
+		case <-u.ctx.Done():
+			_ = conn.Close()
+		case <-conn.CloseChan():
+		}
+	}()
+	return conn, nil
+}
+
+// AccessServiceProxy gets the path of the service through the API server.
+//
+// Deprecated: use AccessServiceProxyWithContext.
 func (e *RemoteExecutor) AccessServiceProxy(namespace, serviceName, path string) (output []byte, err error) {
-	return e.Clientset.CoreV1().RESTClient().Get().
-		Namespace(namespace).
-		Resource("services").
-		Name(serviceName).
-		SubResource("proxy").
-		Suffix(path).DoRaw(context.TODO())
+	return e.AccessServiceProxyWithContext(context.TODO(), namespace, serviceName, path)
+}
+
+func (e *RemoteExecutor) AccessServiceProxyWithContext(ctx context.Context, namespace, serviceName, path string) (output []byte, err error) {
+	err = e.retry(ctx, e.timeout, func(ctx context.Context) (err error) {
+		output, err = e.Clientset.CoreV1().RESTClient().Get().
+			Namespace(namespace).
+			Resource("services").
+			Name(serviceName).
+			SubResource("proxy").
+			Suffix(path).DoRaw(ctx)
+		return
+	})
+	return
+}
+
+// retry calls fn with a timeout for every attempt, and retries it with backoff
+// while it returns transient errors.
+func (e *RemoteExecutor) retry(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	backoff := e.backoff
+	attempts := backoff.Steps
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		err := fn(attemptCtx)
+		cancel()
+
+		if err == nil || !IsTransient(err) || attempt >= attempts || ctx.Err() != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff.Step()):
+		}
+	}
+}
+
+// IsTransient reports whether the error is a transient error of the API
+// server or the network, which is worth retrying. Broken connections are not
+// retried, since the command may have run already.
+func IsTransient(err error) bool {
+	return k8sErrors.IsServerTimeout(err) ||
+		k8sErrors.IsTimeout(err) ||
+		k8sErrors.IsTooManyRequests(err) ||
+		k8sErrors.IsServiceUnavailable(err) ||
+		k8sErrors.IsInternalError(err) ||
+		utilnet.IsConnectionRefused(err)
 }
@@ -0,0 +1,161 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+// fakeStreamExecutor writes the given streams and returns the errors in turn.
+// Like the SPDY executor, it stops streaming once its context is done.
+type fakeStreamExecutor struct {
+	stdout, stderr string
+	errs           []error
+	delay          time.Duration
+	calls          atomic.Int32
+	running        atomic.Int32
+
+	ctx context.Context
+}
+
+func (f *fakeStreamExecutor) Stream(options remotecommand.StreamOptions) error {
+	f.calls.Add(1)
+	f.running.Add(1)
+	defer f.running.Add(-1)
+	select {
+	case <-time.After(f.delay):
+	case <-f.ctx.Done():
+		return errors.New("connection closed")
+	}
+	_, _ = options.Stdout.Write([]byte(f.stdout))
+	_, _ = options.Stderr.Write([]byte(f.stderr))
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+var _ = Describe("executor/remote", func() {
+	var fake *fakeStreamExecutor
+	var e *RemoteExecutor
+	command := Command{Command: "hadmin", Args: []string{"store", "status"}}
+
+	BeforeEach(func() {
+		fake = &fakeStreamExecutor{}
+		e = &RemoteExecutor{
+			timeout: time.Second,
+			backoff: wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 3},
+			newStreamExecutor: func(ctx context.Context, _ *url.URL) (remotecommand.Executor, error) {
+				fake.ctx = ctx
+				return fake, nil
+			},
+		}
+	})
+
+	It("should not fail on stderr output", func() {
+		fake.stdout = "ok"
+		fake.stderr = "warning: deprecated flag"
+
+		result, err := e.exec(context.TODO(), &url.URL{}, command)
+		Expect(err).To(Succeed())
+		Expect(result).To(Equal(&Result{Stdout: "ok", Stderr: "warning: deprecated flag"}))
+	})
+
+	It("should return exit code and both streams", func() {
+		fake.stdout = "partial"
+		fake.stderr = "no such node"
+		fake.errs = []error{utilexec.CodeExitError{Err: errors.New("exit 2"), Code: 2}}
+
+		result, err := e.exec(context.TODO(), &url.URL{}, command)
+		var exitErr *ExitError
+		Expect(errors.As(err, &exitErr)).To(BeTrue())
+		Expect(result).To(Equal(&Result{Stdout: "partial", Stderr: "no such node", ExitCode: 2}))
+		Expect(exitErr.Result).To(Equal(result))
+		Expect(fake.calls.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("should retry transient errors", func() {
+		fake.stdout = "ok"
+		fake.errs = []error{
+			k8sErrors.NewServiceUnavailable("try again"),
+			k8sErrors.NewTooManyRequests("slow down", 1),
+		}
+
+		result, err := e.exec(context.TODO(), &url.URL{}, command)
+		Expect(err).To(Succeed())
+		Expect(result.Stdout).To(Equal("ok"))
+		Expect(fake.calls.Load()).To(BeEquivalentTo(3))
+	})
+
+	It("should give up after the attempts of the backoff", func() {
+		fake.errs = []error{
+			k8sErrors.NewServiceUnavailable("try again"),
+			k8sErrors.NewServiceUnavailable("try again"),
+			k8sErrors.NewServiceUnavailable("try again"),
+		}
+
+		_, err := e.exec(context.TODO(), &url.URL{}, command)
+		Expect(k8sErrors.IsServiceUnavailable(err)).To(BeTrue())
+		Expect(fake.calls.Load()).To(BeEquivalentTo(3))
+	})
+
+	It("should honor the deadline of the call", func() {
+		fake.delay = time.Second
+		command := command
+		command.Timeout = 10 * time.Millisecond
+
+		_, err := e.exec(context.TODO(), &url.URL{}, command)
+		Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+		Expect(fake.calls.Load()).To(BeEquivalentTo(1))
+		Expect(fake.running.Load()).To(BeZero())
+	})
+
+	It("should close the connection once the context is done", func() {
+		conn := &fakeConnection{closed: make(chan bool)}
+		ctx, cancel := context.WithCancel(context.TODO())
+		upgrader := contextUpgrader{ctx: ctx, Upgrader: fakeUpgrader{conn: conn}}
+
+		_, err := upgrader.NewConnection(&http.Response{})
+		Expect(err).To(Succeed())
+		Consistently(conn.CloseChan()).ShouldNot(BeClosed())
+
+		cancel()
+		Eventually(conn.CloseChan()).Should(BeClosed())
+	})
+})
+
+type fakeUpgrader struct {
+	conn httpstream.Connection
+}
+
+func (u fakeUpgrader) NewConnection(*http.Response) (httpstream.Connection, error) {
+	return u.conn, nil
+}
+
+// fakeConnection only implements closing the connection.
+type fakeConnection struct {
+	httpstream.Connection
+	closed chan bool
+}
+
+func (c *fakeConnection) Close() error {
+	close(c.closed)
+	return nil
+}
+
+func (c *fakeConnection) CloseChan() <-chan bool {
+	return c.closed
+}
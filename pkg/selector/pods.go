@@ -18,14 +18,21 @@ package selector
 
 import (
 	"context"
+	"errors"
+	"sort"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func (e *Selector) GetPods(namespace string, labelMap *map[string]string, fieldMap *map[string]string) ([]v1.Pod, error) {
+	if e.reader != nil {
+		return e.getCachedPods(namespace, labelMap, fieldMap)
+	}
+
 	listOptions := metav1.ListOptions{}
 
 	if labelMap != nil {
@@ -46,3 +53,53 @@ func (e *Selector) GetPods(namespace string, labelMap *map[string]string, fieldM
 
 	return podList.Items, nil
 }
+
+func (e *Selector) getCachedPods(namespace string, labelMap *map[string]string, fieldMap *map[string]string) ([]v1.Pod, error) {
+	if fieldMap != nil {
+		return nil, errors.New("field selectors are not supported by the cached selector")
+	}
+
+	opts := []client.ListOption{client.InNamespace(namespace)}
+	if labelMap != nil {
+		opts = append(opts, client.MatchingLabels(*labelMap))
+	}
+
+	podList := &v1.PodList{}
+	if err := e.reader.List(context.TODO(), podList, opts...); err != nil {
+		return nil, err
+	}
+	return podList.Items, nil
+}
+
+// GetReadyPods returns the pods which are running, ready and not terminating,
+// sorted by name.
+func (e *Selector) GetReadyPods(namespace string, labelMap *map[string]string) ([]v1.Pod, error) {
+	pods, err := e.GetPods(namespace, labelMap, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ready := make([]v1.Pod, 0, len(pods))
+	for i := range pods {
+		if IsPodReady(&pods[i]) {
+			ready = append(ready, pods[i])
+		}
+	}
+	sort.Slice(ready, func(i, j int) bool {
+		return ready[i].Name < ready[j].Name
+	})
+	return ready, nil
+}
+
+// IsPodReady reports whether the pod is running, ready and not terminating.
+func IsPodReady(pod *v1.Pod) bool {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase != v1.PodRunning {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
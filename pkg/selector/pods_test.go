@@ -8,6 +8,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var _ = Describe("selector/pods", func() {
@@ -77,4 +78,78 @@ var _ = Describe("selector/pods", func() {
 			Expect(pods[0].Status.Phase).To(Equal(corev1.PodRunning))
 		})
 	})
+
+	Context("GetReadyPods", Ordered, func() {
+		newPod := func(name string, ready bool, phase corev1.PodPhase) *corev1.Pod {
+			status := corev1.ConditionFalse
+			if ready {
+				status = corev1.ConditionTrue
+			}
+			return &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   name,
+					Labels: map[string]string{"app": "admin"},
+				},
+				Status: corev1.PodStatus{
+					Phase:      phase,
+					Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+				},
+			}
+		}
+
+		BeforeAll(func() {
+			for _, pod := range []*corev1.Pod{
+				newPod("admin-2", true, corev1.PodRunning),
+				newPod("admin-1", true, corev1.PodRunning),
+				newPod("admin-pending", false, corev1.PodPending),
+				newPod("admin-unready", false, corev1.PodRunning),
+			} {
+				_, err := clientset.CoreV1().Pods("default").Create(context.TODO(), pod, metav1.CreateOptions{})
+				Expect(err).To(BeNil())
+			}
+		})
+
+		It("should only get ready pods sorted by name", func() {
+			pods, err := selector.GetReadyPods("default", &map[string]string{"app": "admin"})
+
+			Expect(err).To(BeNil())
+			Expect(len(pods)).To(Equal(2))
+			Expect(pods[0].Name).To(Equal("admin-1"))
+			Expect(pods[1].Name).To(Equal("admin-2"))
+		})
+
+		It("should not treat terminating pods as ready", func() {
+			pod := newPod("admin-terminating", true, corev1.PodRunning)
+			now := metav1.Now()
+			pod.DeletionTimestamp = &now
+
+			Expect(IsPodReady(pod)).To(BeFalse())
+		})
+	})
+	Context("NewCachedSelector", func() {
+		var cached *Selector
+
+		BeforeEach(func() {
+			reader := clientfake.NewClientBuilder().WithObjects(
+				&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "admin-0", Namespace: "default", Labels: map[string]string{"app": "admin"}}},
+				&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "admin-0", Namespace: "other", Labels: map[string]string{"app": "admin"}}},
+				&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default", Labels: map[string]string{"app": "nginx"}}},
+			).Build()
+			cached = NewCachedSelector(reader)
+		})
+
+		It("should get pods from the reader", func() {
+			pods, err := cached.GetPods("default", &map[string]string{"app": "admin"}, nil)
+
+			Expect(err).To(BeNil())
+			Expect(len(pods)).To(Equal(1))
+			Expect(pods[0].Namespace).To(Equal("default"))
+		})
+
+		It("should not support field selectors", func() {
+			_, err := cached.GetPods("default", nil, &map[string]string{"status.phase": "Running"})
+
+			Expect(err).NotTo(BeNil())
+		})
+	})
 })
@@ -16,10 +16,17 @@ limitations under the License.
 
 package selector
 
-import "k8s.io/client-go/kubernetes"
+import (
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
 
 type Selector struct {
 	clientset kubernetes.Interface
+
+	// reader lists the pods instead of the clientset if set, e.g. from the
+	// informer cache of the manager.
+	reader client.Reader
 }
 
 func NewSelector(clientset kubernetes.Interface) *Selector {
@@ -27,3 +34,11 @@ func NewSelector(clientset kubernetes.Interface) *Selector {
 		clientset: clientset,
 	}
 }
+
+// NewCachedSelector generates a selector which lists the pods with the reader,
+// field selectors are not supported since the cache has no index for them.
+func NewCachedSelector(reader client.Reader) *Selector {
+	return &Selector{
+		reader: reader,
+	}
+}